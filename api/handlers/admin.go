@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/admin"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/archive"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	adminPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/admin"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary	Export applicant data
+// @Description	Export all the data held about an applicant, identified by phone or email, for a data principal access request
+// @Tags Admin
+// @Accept		json
+// @Produce		json
+// @Security	AdminToken
+// @Param request body adminPayload.ApplicantDataRequest true "request body"
+// @Success 200 {object} adminPayload.ExportApplicantDataResponse
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router	/admin/applicant-data/export	[post]
+func ExportApplicantData(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload adminPayload.ApplicantDataRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		response, err := admin.NewAdmin(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeAdmin)).ExportApplicantData(&payload)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// @Summary	Erase applicant data
+// @Description	Erase the personal data of an applicant, identified by phone or email, for a data principal erasure request, the anonymized applications are kept for the job counters
+// @Tags Admin
+// @Accept		json
+// @Produce		json
+// @Security	AdminToken
+// @Param request body adminPayload.ApplicantDataRequest true "request body"
+// @Success 200 {object} adminPayload.EraseApplicantDataResponse
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router	/admin/applicant-data/erase	[post]
+func EraseApplicantData(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload adminPayload.ApplicantDataRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		response, err := admin.NewAdmin(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeAdmin)).EraseApplicantData(&payload)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// @Summary	Get transaction timeline
+// @Description	Get all the beckn messages received and sent for a transaction, in the order they were exchanged
+// @Tags Admin
+// @Accept		json
+// @Produce		json
+// @Security	AdminToken
+// @Param transactionId path string true "Transaction ID"
+// @Success 200 {object} adminPayload.TransactionTimelineResponse
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router	/admin/transactions/{transactionId}/timeline	[get]
+func GetTransactionTimeline(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		transactionID := c.Param("transactionId")
+
+		response, err := archive.NewArchive(clients.WithContext(c.Request.Context())).GetTransactionTimeline(transactionID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if len(response.Messages) == 0 {
+			c.AbortWithStatusJSON(http.StatusNotFound, "no messages found for transaction "+transactionID)
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/api/middleware"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	auditPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/audit"
+)
+
+// UserIDHeader identifies the employer or admin user calling the APIs, for the audit log
+const UserIDHeader = "X-User-ID"
+
+// @Summary	List audit entries
+// @Description	List the latest audit entries of a business, newest first
+// @Tags Business
+// @Accept		json
+// @Produce		json
+// @Param id path string true "Business ID"
+// @Param actorType query string false "Actor type" Enums(EMPLOYER, BAP, ADMIN, SYSTEM)
+// @Param actorId query string false "Actor ID"
+// @Param action query string false "Action"
+// @Param jobId query string false "Job ID"
+// @Param jobApplicationId query string false "Job Application ID"
+// @Param correlationId query string false "Correlation ID"
+// @Param from query string false "Entries at or after, RFC3339"
+// @Param to query string false "Entries before, RFC3339"
+// @Param limit query int false "Maximum number of entries, 100 by default and up to 1000"
+// @Success 200 {array} dbAudit.AuditEntry
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router	/business/{id}/audit	[get]
+func ListAuditEntries(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		businessID := c.Param("id")
+
+		var payload auditPayload.ListAuditEntriesRequest
+		if err := c.ShouldBindQuery(&payload); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		entries, err := audit.NewAudit(clients.WithContext(c.Request.Context())).ListAuditEntries(businessID, &payload)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
+
+// getAuditSource returns the source of the operations performed for an employer or admin API request
+func getAuditSource(c *gin.Context, actorType dbAudit.ActorType) audit.Source {
+	return audit.Source{
+		Actor: dbAudit.Actor{
+			Type: actorType,
+			ID:   c.GetHeader(UserIDHeader),
+		},
+		CorrelationID: c.GetString(middleware.RequestIDKey),
+	}
+}
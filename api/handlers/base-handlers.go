@@ -4,6 +4,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/health"
+	healthPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/health"
 )
 
 func StatusHandler() gin.HandlerFunc {
@@ -15,3 +18,35 @@ func StatusHandler() gin.HandlerFunc {
 type APIStatus struct {
 	Status string `json:"status"`
 }
+
+// @Summary	Liveness
+// @Description	Reports that the adapter is up, along with its version
+// @Tags Health
+// @Produce		json
+// @Success 200 {object} healthPayload.HealthResponse
+// @Router	/livez	[get]
+func LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, health.NewHealth().Liveness())
+	}
+}
+
+// @Summary	Readiness
+// @Description	Reports whether the adapter can serve requests, along with the status of its database, indexes, background processing backlog and encryption keys
+// @Tags Health
+// @Produce		json
+// @Success 200 {object} healthPayload.HealthResponse
+// @Failure 503 {object} healthPayload.HealthResponse
+// @Router	/readyz	[get]
+func ReadinessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := health.NewHealth().Readiness(c.Request.Context())
+
+		statusCode := http.StatusOK
+		if response.Status != healthPayload.StatusUp {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, response)
+	}
+}
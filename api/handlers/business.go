@@ -6,6 +6,7 @@ import (
 
 	"github.com/ONEST-Network/Job-Manager-Adapter/internal/business"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	businessPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/business"
 	"github.com/gin-gonic/gin"
 )
@@ -27,7 +28,7 @@ func AddBusiness(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		if err := business.NewBusiness(clients).AddBusiness(&payload); err != nil {
+		if err := business.NewBusiness(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).AddBusiness(&payload); err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 			return
 		}
@@ -46,7 +47,7 @@ func ListJobs(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		businessID := c.Param("id")
 
-		jobs, err := business.NewBusiness(clients).ListJobs(businessID)
+		jobs, err := business.NewBusiness(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).ListJobs(businessID)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 			return
@@ -55,3 +56,53 @@ func ListJobs(clients *clients.Clients) gin.HandlerFunc {
 		c.JSON(http.StatusOK, jobs)
 	}
 }
+
+// @Summary	Get business analytics
+// @Description	Get the aggregated ratings of a business and its jobs
+// @Tags Business
+// @Accept		json
+// @Produce		json
+// @Param id path string true "Business ID"
+// @Success 200 {object} businessPayload.GetAnalyticsResponse
+// @Failure 500 {object} string
+// @Router	/business/{id}/analytics	[get]
+func GetBusinessAnalytics(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		businessID := c.Param("id")
+
+		analytics, err := business.NewBusiness(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).GetAnalytics(businessID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, analytics)
+	}
+}
+
+// @Summary	Get candidate applications
+// @Description	Get the application history of a candidate with the business, applications to other businesses are not listed
+// @Tags Business
+// @Accept		json
+// @Produce		json
+// @Param id path string true "Business ID"
+// @Param profileId path string true "Applicant profile ID"
+// @Success 200 {array} businessPayload.CandidateApplication
+// @Failure 500 {object} string
+// @Router	/business/{id}/candidates/{profileId}/applications	[get]
+func GetCandidateApplications(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			businessID = c.Param("id")
+			profileID  = c.Param("profileId")
+		)
+
+		applications, err := business.NewBusiness(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).GetCandidateApplications(businessID, profileID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, applications)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	jobApplication "github.com/ONEST-Network/Job-Manager-Adapter/internal/job-application"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/job-application"
 	"github.com/gin-gonic/gin"
 )
@@ -30,7 +31,34 @@ func UpdateJobApplicationStatus(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		if err := jobApplication.NewJobApplication(clients).UpdateJobApplicationStatus(applicationId, &payload); err != nil {
+		if err := jobApplication.NewJobApplication(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).UpdateJobApplicationStatus(applicationId, &payload); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+}
+
+// @Summary	Extend job offer
+// @Description	Extend an offer to the applicant, or revise the offer already extended
+// @Tags Job Application
+// @Accept		json
+// @Produce		json
+// @Param id path string true "Job Application ID"
+// @Param request body jobapplication.ExtendOfferRequest true "request body"
+// @Success 200
+// @Failure 500 {object} string
+// @Router	/job-application/{id}/offer	[post]
+func ExtendOffer(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		applicationId := c.Param("id")
+
+		var payload jobapplication.ExtendOfferRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := jobApplication.NewJobApplication(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).ExtendOffer(applicationId, &payload); err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 			return
 		}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ONEST-Network/Job-Manager-Adapter/internal/job"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	jobPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/job"
 	"github.com/gin-gonic/gin"
 )
@@ -27,7 +28,7 @@ func CreateJob(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		if err := job.NewJob(clients).CreateJob(&payload); err != nil {
+		if err := job.NewJob(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).CreateJob(&payload); err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 			return
 		}
@@ -46,8 +47,12 @@ func CreateJob(clients *clients.Clients) gin.HandlerFunc {
 func GetJobApplications(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		jobID := c.Param("id")
+		if jobID == "" {
+			// the former /job/applications route
+			jobID = c.Query("id")
+		}
 
-		applications, err := job.NewJob(clients).GetJobApplications(jobID)
+		applications, err := job.NewJob(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).GetJobApplications(jobID)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 			return
@@ -56,3 +61,33 @@ func GetJobApplications(clients *clients.Clients) gin.HandlerFunc {
 		c.JSON(http.StatusOK, applications)
 	}
 }
+
+// @Summary	Close job
+// @Description	Close a filled or withdrawn job, and close all its active applications
+// @Tags Job
+// @Accept		json
+// @Produce		json
+// @Param id path string true "Job ID"
+// @Param request body jobPayload.CloseJobRequest true "request body"
+// @Success 200 {object} jobPayload.CloseJobResponse
+// @Failure 500 {object} string
+// @Router	/job/{id}/close	[post]
+func CloseJob(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobID := c.Param("id")
+
+		var payload jobPayload.CloseJobRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		response, err := job.NewJob(clients.WithContext(c.Request.Context()), getAuditSource(c, dbAudit.ActorTypeEmployer)).CloseJob(jobID, &payload)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
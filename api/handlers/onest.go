@@ -1,13 +1,19 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 
 	"github.com/ONEST-Network/Job-Manager-Adapter/internal/onest"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/async"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/log"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/tracing"
 )
 
 // @Summary	Send jobs
@@ -23,11 +29,17 @@ func SendJobs(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var statusCode = http.StatusOK
 
-		onest := onest.NewOnestClient(clients)
+		slot, ok := reserveAsync(c, "search")
+		if !ok {
+			return
+		}
+		defer slot.Release()
 
-		payload, ack := onest.SendJobsAck(c.Request.Body)
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
+
+		payload, ack := onestClient.SendJobsAck(c.Request.Body)
 		if ack.Error != nil {
-			logrus.Errorf("Error in SendJobsAck: %v", ack.Error.Message)
+			log.WithContext(c.Request.Context()).Errorf("Error in SendJobsAck: %v", ack.Error.Message)
 			statusCode = http.StatusBadRequest
 		}
 
@@ -37,8 +49,7 @@ func SendJobs(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement a message queue to push the payload for processing
-		go onest.SendJobs(payload)
+		processAsync(c, clients, slot, "search", func(onest onest.Interface) { onest.SendJobs(payload) })
 	}
 }
 
@@ -55,11 +66,17 @@ func SendJobFulfillment(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var statusCode = http.StatusOK
 
-		onest := onest.NewOnestClient(clients)
+		slot, ok := reserveAsync(c, "select")
+		if !ok {
+			return
+		}
+		defer slot.Release()
+
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
 
-		payload, ack := onest.SendJobFulfillmentAck(c.Request.Body)
+		payload, ack := onestClient.SendJobFulfillmentAck(c.Request.Body)
 		if ack.Error != nil {
-			logrus.Errorf("Error in SendJobFulfillmentAck: %v", ack.Error.Message)
+			log.WithContext(c.Request.Context()).Errorf("Error in SendJobFulfillmentAck: %v", ack.Error.Message)
 			statusCode = http.StatusBadRequest
 		}
 
@@ -69,8 +86,7 @@ func SendJobFulfillment(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement a message queue to push the payload for processing
-		go onest.SendJobFulfillment(payload)
+		processAsync(c, clients, slot, "select", func(onest onest.Interface) { onest.SendJobFulfillment(payload) })
 	}
 }
 
@@ -87,11 +103,17 @@ func InitializeJobApplication(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var statusCode = http.StatusOK
 
-		onest := onest.NewOnestClient(clients)
+		slot, ok := reserveAsync(c, "init")
+		if !ok {
+			return
+		}
+		defer slot.Release()
+
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
 
-		payload, ack := onest.InitializeJobApplicationAck(c.Request.Body)
+		payload, ack := onestClient.InitializeJobApplicationAck(c.Request.Body)
 		if ack.Error != nil {
-			logrus.Errorf("Error in InitializeJobApplicationAck: %v", ack.Error.Message)
+			log.WithContext(c.Request.Context()).Errorf("Error in InitializeJobApplicationAck: %v", ack.Error.Message)
 			statusCode = http.StatusBadRequest
 		}
 
@@ -101,8 +123,7 @@ func InitializeJobApplication(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement a message queue to push the payload for processing
-		go onest.InitializeJobApplication(payload)
+		processAsync(c, clients, slot, "init", func(onest onest.Interface) { onest.InitializeJobApplication(payload) })
 	}
 }
 
@@ -119,11 +140,17 @@ func ConfirmJobApplication(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var statusCode = http.StatusOK
 
-		onest := onest.NewOnestClient(clients)
+		slot, ok := reserveAsync(c, "confirm")
+		if !ok {
+			return
+		}
+		defer slot.Release()
 
-		payload, initJobApplication, ack := onest.ConfirmJobApplicationAck(c.Request.Body)
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
+
+		payload, initJobApplication, ack := onestClient.ConfirmJobApplicationAck(c.Request.Body)
 		if ack.Error != nil {
-			logrus.Errorf("Error in ConfirmJobApplicationAck: %v", ack.Error.Message)
+			log.WithContext(c.Request.Context()).Errorf("Error in ConfirmJobApplicationAck: %v", ack.Error.Message)
 			statusCode = http.StatusBadRequest
 		}
 
@@ -133,8 +160,7 @@ func ConfirmJobApplication(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement a message queue to push the payload for processing
-		go onest.ConfirmJobApplication(payload, initJobApplication)
+		processAsync(c, clients, slot, "confirm", func(onest onest.Interface) { onest.ConfirmJobApplication(payload, initJobApplication) })
 	}
 }
 
@@ -151,11 +177,17 @@ func JobApplicationStatus(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var statusCode = http.StatusOK
 
-		onest := onest.NewOnestClient(clients)
+		slot, ok := reserveAsync(c, "status")
+		if !ok {
+			return
+		}
+		defer slot.Release()
 
-		payload, ack := onest.JobApplicationStatusAck(c.Request.Body)
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
+
+		payload, ack := onestClient.JobApplicationStatusAck(c.Request.Body)
 		if ack.Error != nil {
-			logrus.Errorf("Error in JobApplicationStatusAck: %v", ack.Error.Message)
+			log.WithContext(c.Request.Context()).Errorf("Error in JobApplicationStatusAck: %v", ack.Error.Message)
 			statusCode = http.StatusBadRequest
 		}
 
@@ -165,8 +197,7 @@ func JobApplicationStatus(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement a message queue to push the payload for processing
-		go onest.JobApplicationStatus(payload)
+		processAsync(c, clients, slot, "status", func(onest onest.Interface) { onest.JobApplicationStatus(payload) })
 	}
 }
 
@@ -183,11 +214,54 @@ func WithdrawJobApplication(clients *clients.Clients) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var statusCode = http.StatusOK
 
-		onest := onest.NewOnestClient(clients)
+		slot, ok := reserveAsync(c, "cancel")
+		if !ok {
+			return
+		}
+		defer slot.Release()
+
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
+
+		payload, ack := onestClient.WithdrawJobApplicationAck(c.Request.Body)
+		if ack.Error != nil {
+			log.WithContext(c.Request.Context()).Errorf("Error in WithdrawJobApplicationAck: %v", ack.Error.Message)
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ack)
+
+		if ack.Error != nil {
+			return
+		}
+
+		processAsync(c, clients, slot, "cancel", func(onest onest.Interface) { onest.WithdrawJobApplication(payload) })
+	}
+}
+
+// @Summary	Update job application
+// @Description	Update job application
+// @Tags ONEST Network
+// @Accept		json
+// @Produce		json
+// @Param request body request.UpdateRequest true "request body"
+// @Success 200 {object} response.UpdateResponse
+// @Failure 500 {object} string
+// @Router	/update	[post]
+func UpdateJobApplication(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var statusCode = http.StatusOK
+
+		slot, ok := reserveAsync(c, "update")
+		if !ok {
+			return
+		}
+		defer slot.Release()
 
-		payload, ack := onest.WithdrawJobApplicationAck(c.Request.Body)
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
+
+		payload, jobApplication, ack := onestClient.UpdateJobApplicationAck(c.Request.Body)
 		if ack.Error != nil {
-			logrus.Errorf("Error in WithdrawJobApplicationAck: %v", ack.Error.Message)
+			log.WithContext(c.Request.Context()).Errorf("Error in UpdateJobApplicationAck: %v", ack.Error.Message)
 			statusCode = http.StatusBadRequest
 		}
 
@@ -197,7 +271,142 @@ func WithdrawJobApplication(clients *clients.Clients) gin.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement a message queue to push the payload for processing
-		go onest.WithdrawJobApplication(payload)
+		processAsync(c, clients, slot, "update", func(onest onest.Interface) { onest.UpdateJobApplication(payload, jobApplication) })
 	}
 }
+
+// @Summary	Rate employer
+// @Description	Rate the employer, and optionally the job, of a job application
+// @Tags ONEST Network
+// @Accept		json
+// @Produce		json
+// @Param request body request.RatingRequest true "request body"
+// @Success 200 {object} response.RatingResponse
+// @Failure 500 {object} string
+// @Router	/rating	[post]
+func SubmitRating(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var statusCode = http.StatusOK
+
+		slot, ok := reserveAsync(c, "rating")
+		if !ok {
+			return
+		}
+		defer slot.Release()
+
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
+
+		payload, ack := onestClient.SubmitRatingAck(c.Request.Body)
+		if ack.Error != nil {
+			log.WithContext(c.Request.Context()).Errorf("Error in SubmitRatingAck: %v", ack.Error.Message)
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ack)
+
+		if ack.Error != nil {
+			return
+		}
+
+		processAsync(c, clients, slot, "rating", func(onest onest.Interface) { onest.SubmitRating(payload) })
+	}
+}
+
+// @Summary	Send support contacts
+// @Description	Send the support contacts of the employer, or the adapter wide support contacts
+// @Tags ONEST Network
+// @Accept		json
+// @Produce		json
+// @Param request body request.SupportRequest true "request body"
+// @Success 200 {object} response.SupportResponse
+// @Failure 500 {object} string
+// @Router	/support	[post]
+func SendSupport(clients *clients.Clients) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var statusCode = http.StatusOK
+
+		slot, ok := reserveAsync(c, "support")
+		if !ok {
+			return
+		}
+		defer slot.Release()
+
+		onestClient := onest.NewOnestClient(clients.WithContext(c.Request.Context()))
+
+		payload, ack := onestClient.SendSupportAck(c.Request.Body)
+		if ack.Error != nil {
+			log.WithContext(c.Request.Context()).Errorf("Error in SendSupportAck: %v", ack.Error.Message)
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, ack)
+
+		if ack.Error != nil {
+			return
+		}
+
+		processAsync(c, clients, slot, "support", func(onest onest.Interface) { onest.SendSupport(payload) })
+	}
+}
+
+// reserveAsync reserves a worker for the background processing of a beckn request before it is acked,
+// the request is NACKed with a 429 for the BAP to retry later when the workers of the action are saturated
+func reserveAsync(c *gin.Context, action string) (*async.Slot, bool) {
+	slot, ok := asyncPool(action).Reserve()
+	if !ok {
+		log.WithContext(c.Request.Context()).Warnf("Rejected %s request, the workers are saturated", action)
+		metrics.RejectAsync(action)
+
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"message": gin.H{"ack": gin.H{"status": "NACK"}},
+			"error": gin.H{
+				"code":    "10000",
+				"paths":   "",
+				"message": fmt.Sprintf("too many %s requests being processed, retry later", action),
+			},
+		})
+	}
+
+	return slot, ok
+}
+
+// asyncPool returns the worker pool of an action, sized by the configuration
+func asyncPool(action string) *async.Pool {
+	return async.PoolOf(action, func() (int, int) {
+		workers, ok := config.Config.AsyncActionWorkers[action]
+		if !ok {
+			workers = config.Config.AsyncWorkers
+		}
+
+		queueSize, ok := config.Config.AsyncActionQueueSizes[action]
+		if !ok {
+			queueSize = config.Config.AsyncQueueSize
+		}
+
+		return workers, queueSize
+	})
+}
+
+// processAsync processes an acked beckn request in the background on the slot reserved for it, in a span
+// of the request trace, the processing is waited for on shutdown
+func processAsync(c *gin.Context, clients *clients.Clients, slot *async.Slot, action string, process func(onest onest.Interface)) {
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	name := action
+	if message := tracing.GetMessage(ctx); message != nil {
+		name = fmt.Sprintf("%s of transaction %s message %s", action, message.TransactionID, message.MessageID)
+	}
+
+	dequeue := metrics.QueueAsync(action)
+	slot.Submit(name, func() {
+		dequeue()
+
+		metrics.TrackAsync(action, func() {
+			ctx, span := tracing.Start(ctx, "process "+action)
+			defer span.End()
+
+			process(onest.NewOnestClient(clients.WithContext(ctx)))
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+)
+
+// ValidateAdminToken allows only the requests with the admin token as their bearer token,
+// the admin APIs are disabled when no admin token is configured
+func ValidateAdminToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if config.Config.AdminToken == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Admin APIs are disabled",
+			})
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(config.Config.AdminToken)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid admin token",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
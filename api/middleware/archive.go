@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/archive"
+	apiclient "github.com/ONEST-Network/Job-Manager-Adapter/pkg/api-client"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/async"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+)
+
+// ArchiveMessages archives the beckn requests received from the BAPs along with their acks, in the
+// background so the acks aren't delayed
+func ArchiveMessages(clients *clients.Clients) gin.HandlerFunc {
+	archiver := archive.NewArchive(clients)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		writer := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		exchange := &apiclient.Exchange{
+			Method:         c.Request.Method,
+			URL:            c.Request.URL.String(),
+			RequestHeaders: c.Request.Header.Clone(),
+			RequestBody:    body,
+			StatusCode:     writer.Status(),
+			ResponseBody:   writer.body.Bytes(),
+			Latency:        time.Since(start),
+		}
+
+		async.Go("archive of "+c.Request.URL.Path, func() { archiver.ArchiveInbound(exchange) })
+	}
+}
+
+// bodyRecorder keeps a copy of the response body written by the handlers
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *bodyRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -5,16 +5,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/log"
 )
 
-// DefaultStructuredLogger logs a gin HTTP request in JSON format. Uses the
-// default logger from rs/zerolog.
+// DefaultStructuredLogger logs a gin HTTP request with the standard logrus logger.
 func DefaultStructuredLogger() gin.HandlerFunc {
-	return StructuredLogger(&logrus.Logger{})
+	return StructuredLogger(logrus.StandardLogger())
 }
 
-// StructuredLogger logs a gin HTTP request in JSON format. Allows to set the
-// logger for testing purposes.
+// StructuredLogger logs a gin HTTP request along with its request id and beckn message.
+// Allows to set the logger for testing purposes.
 func StructuredLogger(logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
@@ -54,10 +55,12 @@ func StructuredLogger(logger *logrus.Logger) gin.HandlerFunc {
 			"error":     param.ErrorMessage,
 		}
 
+		entry := logger.WithFields(log.Fields(c.Request.Context())).WithFields(logFields)
+
 		if c.Writer.Status() >= 500 {
-			logrus.WithFields(logFields).Error()
+			entry.Error()
 		} else {
-			logrus.WithFields(logFields).Info()
+			entry.Info()
 		}
 
 	}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
+)
+
+// BecknMetrics records the beckn requests by action, the handlers NACK with a non 200 status
+func BecknMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		action := strings.TrimPrefix(c.FullPath(), "/")
+		if action == "" {
+			return
+		}
+
+		metrics.ObserveBecknRequest(action, c.Writer.Status() == http.StatusOK, time.Since(start))
+	}
+}
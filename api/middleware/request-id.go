@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/log"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
+)
+
+const (
+	// RequestIDHeader carries the id correlating a request across the adapter and its callers
+	RequestIDHeader = "X-Request-ID"
+
+	// RequestIDKey is the key of the request id in the gin context
+	RequestIDKey = "requestId"
+)
+
+// RequestID reuses the request id sent by the caller or generates one, and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = random.GetRandomString(16)
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(log.WithRequestID(c.Request.Context(), requestID))
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/tracing"
+)
+
+// Trace starts a span for every request, continuing the trace of the caller if any
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+
+		ctx, span := tracing.Start(tracing.Extract(c.Request.Context(), c.Request.Header), c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("request.id", c.GetString(RequestIDKey)),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// BecknContext adds the context of the beckn message in the request to the request context, so the
+// spans of its processing carry its transaction and message ids
+func BecknContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var request struct {
+			Context struct {
+				Action        string `json:"action"`
+				TransactionID string `json:"transaction_id"`
+				MessageID     string `json:"message_id"`
+				BapID         string `json:"bap_id"`
+			} `json:"context"`
+		}
+
+		// the invalid requests are NACKed by the handlers
+		if err := json.Unmarshal(body, &request); err == nil {
+			ctx := tracing.WithMessage(c.Request.Context(), &tracing.Message{
+				Action:        request.Context.Action,
+				TransactionID: request.Context.TransactionID,
+				MessageID:     request.Context.MessageID,
+				BapID:         request.Context.BapID,
+			})
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/ONEST-Network/Job-Manager-Adapter/api/handlers"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/gin-gonic/gin"
+)
+
+func AdminRouter(router *gin.RouterGroup, clients *clients.Clients) {
+	router.POST("/applicant-data/export", handlers.ExportApplicantData(clients))
+	router.POST("/applicant-data/erase", handlers.EraseApplicantData(clients))
+	router.GET("/transactions/:transactionId/timeline", handlers.GetTransactionTimeline(clients))
+}
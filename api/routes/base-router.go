@@ -7,10 +7,14 @@ import (
 
 	"github.com/ONEST-Network/Job-Manager-Adapter/api/handlers"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
 )
 
 func BaseRouter(router *gin.RouterGroup, clients *clients.Clients) {
 	// general routers
 	router.GET("/status", handlers.StatusHandler())
+	router.GET("/livez", handlers.LivenessHandler())
+	router.GET("/readyz", handlers.ReadinessHandler())
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 }
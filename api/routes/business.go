@@ -9,4 +9,7 @@ import (
 func BusinessRouter(router *gin.RouterGroup, clients *clients.Clients) {
 	router.POST("/add", handlers.AddBusiness(clients))
 	router.GET("/:id/jobs", handlers.ListJobs(clients))
+	router.GET("/:id/analytics", handlers.GetBusinessAnalytics(clients))
+	router.GET("/:id/candidates/:profileId/applications", handlers.GetCandidateApplications(clients))
+	router.GET("/:id/audit", handlers.ListAuditEntries(clients))
 }
@@ -8,4 +8,5 @@ import (
 
 func JobApplicationRouter(router *gin.RouterGroup, clients *clients.Clients) {
 	router.POST("/:id/status", handlers.UpdateJobApplicationStatus(clients))
+	router.POST("/:id/offer", handlers.ExtendOffer(clients))
 }
@@ -8,5 +8,7 @@ import (
 
 func JobRouter(router *gin.RouterGroup, clients *clients.Clients) {
 	router.POST("/create", handlers.CreateJob(clients))
-	router.GET("/applications", handlers.GetJobApplications(clients))
+	router.GET("/:id/applications", handlers.GetJobApplications(clients))
+	router.GET("/applications", handlers.GetJobApplications(clients)) // Deprecated: the job id as the id query parameter
+	router.POST("/:id/close", handlers.CloseJob(clients))
 }
@@ -13,4 +13,7 @@ func BecknRouter(router *gin.RouterGroup, clients *clients.Clients) {
 	router.POST("/confirm", handlers.ConfirmJobApplication(clients))
 	router.POST("/status", handlers.JobApplicationStatus(clients))
 	router.POST("/cancel", handlers.WithdrawJobApplication(clients))
+	router.POST("/update", handlers.UpdateJobApplication(clients))
+	router.POST("/rating", handlers.SubmitRating(clients))
+	router.POST("/support", handlers.SendSupport(clients))
 }
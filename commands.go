@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/job"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/replay"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbMessageArchive "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/message-archive"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+)
+
+// runCommand runs a maintenance command instead of the server, for eg. 'adapter reconcile'
+func runCommand(clients *clients.Clients, name string, args []string) {
+	switch name {
+	// recompute the job counters from the job applications, best run while no applications are processed
+	case "reconcile":
+		if err := job.NewJob(clients, audit.SystemSource("reconcile")).ReconcileJobCounters(); err != nil {
+			logrus.Fatalf("Failed to reconcile job counters, %v", err)
+		}
+	// encrypt the applicant details stored in plain text or with a rotated key, after adding the new key
+	// to the key file and making it the active key
+	case "rotate-keys":
+		rotateKeys(clients)
+	// replay archived beckn requests, or a JSONL file of them, and report the callbacks and their diffs
+	case "replay":
+		replayMessages(clients, args)
+	// print a new random key for the key file
+	case "generate-key":
+		key, err := encryption.GenerateKey()
+		if err != nil {
+			logrus.Fatalf("Failed to generate key, %v", err)
+		}
+		fmt.Println(key)
+	default:
+		logrus.Fatalf("Unknown command %s", name)
+	}
+}
+
+func replayMessages(clients *clients.Clients, args []string) {
+	var (
+		flags         = flag.NewFlagSet("replay", flag.ExitOnError)
+		transactionID = flags.String("transaction", "", "id of the archived transaction to replay")
+		file          = flags.String("file", "", "JSONL file of the messages to replay")
+		write         = flags.Bool("write", false, "allow the direct replay to write to the configured database")
+		options       replay.Options
+	)
+
+	flags.StringVar(&options.Target, "target", "", "base url of a running adapter to replay against, the messages are replayed directly when empty")
+	flags.StringVar(&options.CallbackAddr, "callback-addr", "localhost:0", "address to capture the callbacks of the target adapter on")
+	flags.DurationVar(&options.Wait, "wait", 10*time.Second, "how long to wait for the callback of a message from the target adapter")
+	_ = flags.Parse(args)
+
+	if (*transactionID == "") == (*file == "") {
+		logrus.Fatal("Either -transaction or -file must be provided")
+	}
+
+	// the handlers replayed directly update the jobs and the job applications of the configured database
+	if options.Target == "" && !*write {
+		logrus.Fatal("The direct replay writes to the configured database, pass -write to allow it or replay against a running adapter with -target")
+	}
+
+	replayer := replay.NewReplay(clients, options)
+
+	var (
+		messages []dbMessageArchive.Message
+		err      error
+	)
+
+	if *transactionID != "" {
+		messages, err = replayer.LoadTransaction(*transactionID)
+	} else {
+		messages, err = replayer.LoadFile(*file)
+	}
+	if err != nil {
+		logrus.Fatalf("Failed to load messages, %v", err)
+	}
+
+	if err := replayer.Replay(messages, os.Stdout); err != nil {
+		logrus.Fatalf("Failed to replay messages, %v", err)
+	}
+}
+
+func rotateKeys(clients *clients.Clients) {
+	if !encryption.Enabled() {
+		logrus.Fatal("Encryption is not enabled, no key file provided")
+	}
+
+	// the collections are re-encrypted in the same order on every run
+	for _, collection := range []struct {
+		name      string
+		reencrypt func() (int, error)
+	}{
+		{name: "job applications", reencrypt: clients.JobApplicationClient.ReencryptJobApplications},
+		{name: "init job applications", reencrypt: clients.InitJobApplicationClient.ReencryptInitJobApplications},
+		{name: "applicant profiles", reencrypt: func() (int, error) { return reencryptApplicantProfiles(clients) }},
+	} {
+		count, err := collection.reencrypt()
+		if err != nil {
+			logrus.Fatalf("Failed to re-encrypt %s after %d documents, %v", collection.name, count, err)
+		}
+
+		logrus.Infof("Re-encrypted %d %s with %s key", count, collection.name, encryption.ActiveKeyID())
+	}
+}
+
+// reencryptApplicantProfiles re-encrypts the applicant profiles, and links the job applications of the
+// profiles merged on their new keys to the profiles they were merged into
+func reencryptApplicantProfiles(clients *clients.Clients) (int, error) {
+	count, merged, err := clients.ApplicantProfileClient.ReencryptApplicantProfiles()
+
+	for from, into := range merged {
+		var (
+			query  = bson.D{{Key: "profile_id", Value: from}}
+			update = bson.D{{Key: "$set", Value: bson.D{{Key: "profile_id", Value: into}}}}
+		)
+
+		if _, updateErr := clients.JobApplicationClient.UpdateJobApplications(query, update); updateErr != nil {
+			logrus.Errorf("Failed to link the job applications of applicant profile %s to %s, %v", from, into, updateErr)
+			continue
+		}
+
+		logrus.Infof("Merged applicant profile %s into %s", from, into)
+	}
+
+	return count, err
+}
@@ -0,0 +1,258 @@
+package admin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	applicantProfileDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/applicant-profile"
+	auditDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	initJobApplicationDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/init-job-application"
+	jobApplicationDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	adminPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/admin"
+)
+
+type Interface interface {
+	ExportApplicantData(payload *adminPayload.ApplicantDataRequest) (*adminPayload.ExportApplicantDataResponse, error)
+	EraseApplicantData(payload *adminPayload.ApplicantDataRequest) (*adminPayload.EraseApplicantDataResponse, error)
+}
+
+type Admin struct {
+	clients *clients.Clients
+	source  audit.Source
+}
+
+func NewAdmin(clients *clients.Clients, source audit.Source) Interface {
+	return &Admin{
+		clients: clients,
+		source:  source,
+	}
+}
+
+// ExportApplicantData collects all the data held about an applicant, for a data principal access request
+func (a *Admin) ExportApplicantData(payload *adminPayload.ApplicantDataRequest) (*adminPayload.ExportApplicantDataResponse, error) {
+	logrus.Infof("[Request]: Received request to export applicant data")
+
+	query, err := getApplicantQuery(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &adminPayload.ExportApplicantDataResponse{
+		GeneratedAt: time.Now(),
+	}
+
+	if response.InitJobApplications, err = a.clients.InitJobApplicationClient.ListInitJobApplication(query); err != nil {
+		logrus.Errorf("Failed to list init job applications, %v", err)
+		return nil, fmt.Errorf("failed to list init job applications, %v", err)
+	}
+
+	if response.JobApplications, err = a.clients.JobApplicationClient.ListJobApplication(query); err != nil {
+		logrus.Errorf("Failed to list job applications, %v", err)
+		return nil, fmt.Errorf("failed to list job applications, %v", err)
+	}
+
+	var jobApplicationIDs, profileIDs []string
+	for _, jobApplication := range response.JobApplications {
+		jobApplicationIDs = append(jobApplicationIDs, jobApplication.ID)
+		if jobApplication.ProfileID != "" {
+			profileIDs = append(profileIDs, jobApplication.ProfileID)
+		}
+	}
+
+	if response.ApplicantProfiles, err = a.clients.ApplicantProfileClient.ListApplicantProfiles(getProfileQuery(payload, profileIDs)); err != nil {
+		logrus.Errorf("Failed to list applicant profiles, %v", err)
+		return nil, fmt.Errorf("failed to list applicant profiles, %v", err)
+	}
+
+	if len(jobApplicationIDs) > 0 {
+		if response.Ratings, err = a.clients.RatingClient.ListRatings(bson.D{{Key: "job_application_id", Value: bson.D{{Key: "$in", Value: jobApplicationIDs}}}}); err != nil {
+			logrus.Errorf("Failed to list ratings, %v", err)
+			return nil, fmt.Errorf("failed to list ratings, %v", err)
+		}
+
+		if response.AuditEntries, err = a.clients.AuditClient.ListAuditEntries(bson.D{{Key: "target.job_application_id", Value: bson.D{{Key: "$in", Value: jobApplicationIDs}}}}, 0); err != nil {
+			logrus.Errorf("Failed to list audit entries, %v", err)
+			return nil, fmt.Errorf("failed to list audit entries, %v", err)
+		}
+	}
+
+	if transactionIDs := getTransactionIDs(response.JobApplications, response.InitJobApplications); len(transactionIDs) > 0 {
+		if response.Messages, err = a.clients.MessageArchiveClient.ListMessages(bson.D{{Key: "transaction_id", Value: bson.D{{Key: "$in", Value: transactionIDs}}}}); err != nil {
+			logrus.Errorf("Failed to list archived messages, %v", err)
+			return nil, fmt.Errorf("failed to list archived messages, %v", err)
+		}
+	}
+
+	logrus.Infof("Exported %d job applications, %d init job applications and %d archived messages of an applicant",
+		len(response.JobApplications), len(response.InitJobApplications), len(response.Messages))
+
+	return response, nil
+}
+
+// EraseApplicantData erases the personal data of an applicant, for a data principal erasure request, the job
+// applications are anonymized instead of deleted so the job counters and the analytics stay accurate
+func (a *Admin) EraseApplicantData(payload *adminPayload.ApplicantDataRequest) (*adminPayload.EraseApplicantDataResponse, error) {
+	logrus.Infof("[Request]: Received request to erase applicant data")
+
+	query, err := getApplicantQuery(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var response adminPayload.EraseApplicantDataResponse
+
+	jobApplications, err := a.clients.JobApplicationClient.ListJobApplication(query)
+	if err != nil {
+		logrus.Errorf("Failed to list job applications, %v", err)
+		return nil, fmt.Errorf("failed to list job applications, %v", err)
+	}
+
+	// the profile ids are unset by the erasure, the profiles are deleted after it
+	var profileIDs []string
+	for _, jobApplication := range jobApplications {
+		if jobApplication.ProfileID != "" {
+			profileIDs = append(profileIDs, jobApplication.ProfileID)
+		}
+	}
+
+	for _, jobApplication := range jobApplications {
+		var (
+			now    = time.Now()
+			query  = bson.D{{Key: "id", Value: jobApplication.ID}}
+			update = bson.D{
+				{Key: "$set", Value: bson.D{
+					{Key: "applicant_details", Value: jobApplicationDb.ApplicantDetails{}},
+					{Key: "erased_at", Value: now},
+					{Key: "updated_at", Value: now},
+				}},
+				{Key: "$unset", Value: bson.D{
+					{Key: "applicant_keys", Value: ""},
+					{Key: "application_keys", Value: ""},
+					{Key: "profile_id", Value: ""},
+				}},
+			}
+		)
+
+		erased, err := a.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, update)
+		if err != nil {
+			logrus.Errorf("Failed to erase job application %s, %v", jobApplication.ID, err)
+			return nil, fmt.Errorf("failed to erase job application %s, %v", jobApplication.ID, err)
+		}
+		response.ErasedJobApplications++
+
+		audit.NewAudit(a.clients).Record(a.source, auditDb.ActionJobApplicationErased, auditDb.Target{JobID: jobApplication.JobID, JobApplicationID: jobApplication.ID}, &jobApplication, erased)
+	}
+
+	initJobApplications, err := a.clients.InitJobApplicationClient.ListInitJobApplication(query)
+	if err != nil {
+		logrus.Errorf("Failed to list init job applications, %v", err)
+		return nil, fmt.Errorf("failed to list init job applications, %v", err)
+	}
+
+	// the archived messages of the transactions carry the personal details of the applicant as well
+	if transactionIDs := getTransactionIDs(jobApplications, initJobApplications); len(transactionIDs) > 0 {
+		deleted, err := a.clients.MessageArchiveClient.DeleteMessages(bson.D{{Key: "transaction_id", Value: bson.D{{Key: "$in", Value: transactionIDs}}}})
+		if err != nil {
+			logrus.Errorf("Failed to delete archived messages, %v", err)
+			return nil, fmt.Errorf("failed to delete archived messages, %v", err)
+		}
+		response.DeletedMessages = int(deleted)
+	}
+
+	for _, initJobApplication := range initJobApplications {
+		if err := a.clients.InitJobApplicationClient.DeleteInitJobApplication(initJobApplication.TransactionID); err != nil {
+			logrus.Errorf("Failed to delete init job application %s, %v", initJobApplication.TransactionID, err)
+			return nil, fmt.Errorf("failed to delete init job application %s, %v", initJobApplication.TransactionID, err)
+		}
+		response.DeletedInitJobApplications++
+	}
+
+	profiles, err := a.clients.ApplicantProfileClient.ListApplicantProfiles(getProfileQuery(payload, profileIDs))
+	if err != nil {
+		logrus.Errorf("Failed to list applicant profiles, %v", err)
+		return nil, fmt.Errorf("failed to list applicant profiles, %v", err)
+	}
+
+	for _, profile := range profiles {
+		if err := a.clients.ApplicantProfileClient.DeleteApplicantProfile(profile.ID); err != nil {
+			logrus.Errorf("Failed to delete applicant profile %s, %v", profile.ID, err)
+			return nil, fmt.Errorf("failed to delete applicant profile %s, %v", profile.ID, err)
+		}
+		response.DeletedApplicantProfiles++
+	}
+
+	logrus.Infof("Erased %d job applications, %d init job applications, %d applicant profiles and %d archived messages of an applicant",
+		response.ErasedJobApplications, response.DeletedInitJobApplications, response.DeletedApplicantProfiles, response.DeletedMessages)
+
+	return &response, nil
+}
+
+// getApplicantQuery matches the documents of the applicant on the blind indexes of the phone or email
+func getApplicantQuery(payload *adminPayload.ApplicantDataRequest) (bson.D, error) {
+	var (
+		applicantKeys = jobApplicationDb.GetApplicantKeys(payload.Phone, payload.Email)
+		keys          bson.A
+	)
+
+	if applicantKeys.Phone != "" {
+		keys = append(keys, bson.D{{Key: "applicant_keys.phone", Value: applicantKeys.Phone}})
+	}
+
+	if applicantKeys.Email != "" {
+		keys = append(keys, bson.D{{Key: "applicant_keys.email", Value: applicantKeys.Email}})
+	}
+
+	if keys == nil {
+		return nil, fmt.Errorf("phone or email is required")
+	}
+
+	return bson.D{{Key: "$or", Value: keys}}, nil
+}
+
+// getTransactionIDs returns the ids of the transactions of the job applications and the init job applications
+func getTransactionIDs(jobApplications []jobApplicationDb.JobApplication, initJobApplications []initJobApplicationDb.InitJobApplication) bson.A {
+	var transactionIDs bson.A
+
+	for _, jobApplication := range jobApplications {
+		if jobApplication.BecknContext != nil && jobApplication.BecknContext.TransactionID != "" {
+			transactionIDs = append(transactionIDs, jobApplication.BecknContext.TransactionID)
+		}
+	}
+
+	for _, initJobApplication := range initJobApplications {
+		transactionIDs = append(transactionIDs, initJobApplication.TransactionID)
+	}
+
+	return transactionIDs
+}
+
+// getProfileQuery matches the profiles keyed by either the phone or the email of the applicant, along with
+// the profiles of the job applications of the applicant, which may be keyed by a contact detail not given
+func getProfileQuery(payload *adminPayload.ApplicantDataRequest, profileIDs []string) bson.D {
+	var (
+		phoneKey = applicantProfileDb.GetKey(jobApplicationDb.GetApplicantKeys(payload.Phone, ""))
+		emailKey = applicantProfileDb.GetKey(jobApplicationDb.GetApplicantKeys("", payload.Email))
+		keys     = bson.A{}
+		ids      = bson.A{}
+	)
+
+	for _, key := range []string{phoneKey, emailKey} {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, id := range profileIDs {
+		ids = append(ids, id)
+	}
+
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "key", Value: bson.D{{Key: "$in", Value: keys}}}},
+		bson.D{{Key: "id", Value: bson.D{{Key: "$in", Value: ids}}}},
+	}}}
+}
@@ -0,0 +1,170 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+
+	apiclient "github.com/ONEST-Network/Job-Manager-Adapter/pkg/api-client"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbMessageArchive "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/message-archive"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/redact"
+	adminPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/admin"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
+)
+
+type Interface interface {
+	ArchiveInbound(exchange *apiclient.Exchange)
+	ArchiveOutbound(exchange *apiclient.Exchange)
+	GetTransactionTimeline(transactionID string) (*adminPayload.TransactionTimelineResponse, error)
+}
+
+type Archive struct {
+	clients *clients.Clients
+}
+
+func NewArchive(clients *clients.Clients) Interface {
+	return &Archive{
+		clients: clients,
+	}
+}
+
+// becknMessage is the part of a beckn message identifying it
+type becknMessage struct {
+	Context struct {
+		Action        string `json:"action"`
+		BapID         string `json:"bap_id"`
+		TransactionID string `json:"transaction_id"`
+		MessageID     string `json:"message_id"`
+	} `json:"context"`
+}
+
+// becknAck is the sync response to a beckn message
+type becknAck struct {
+	Message struct {
+		Ack struct {
+			Status string `json:"status"`
+		} `json:"ack"`
+	} `json:"message"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ArchiveInbound archives a beckn request received from a BAP along with its ack
+func (a *Archive) ArchiveInbound(exchange *apiclient.Exchange) {
+	a.archive(dbMessageArchive.DirectionInbound, exchange)
+}
+
+// ArchiveOutbound archives a beckn callback sent to a BAP along with its ack, it is an api client observer
+func (a *Archive) ArchiveOutbound(exchange *apiclient.Exchange) {
+	a.archive(dbMessageArchive.DirectionOutbound, exchange)
+}
+
+// archive stores a beckn message exchange with its personal details masked, archiving is best effort and
+// the messages which aren't beckn messages are skipped
+func (a *Archive) archive(direction dbMessageArchive.Direction, exchange *apiclient.Exchange) {
+	message := getMessage(direction, exchange)
+	if message == nil {
+		return
+	}
+
+	if err := a.clients.MessageArchiveClient.CreateMessage(message); err != nil {
+		logrus.Errorf("[Archive]: Failed to archive %s %s message of %s transaction, %v", direction, message.Action, message.TransactionID, err)
+	}
+}
+
+// getMessage returns the archived message of a beckn message exchange, with its personal details and its
+// credentials masked, it returns nil when the exchange isn't a beckn message
+func getMessage(direction dbMessageArchive.Direction, exchange *apiclient.Exchange) *dbMessageArchive.Message {
+	var request becknMessage
+	if err := json.Unmarshal(exchange.RequestBody, &request); err != nil || request.Context.TransactionID == "" {
+		return nil
+	}
+
+	message := &dbMessageArchive.Message{
+		ID:            random.GetRandomString(12),
+		TransactionID: request.Context.TransactionID,
+		MessageID:     request.Context.MessageID,
+		Action:        request.Context.Action,
+		Direction:     direction,
+		BapID:         request.Context.BapID,
+		Method:        exchange.Method,
+		URL:           exchange.URL,
+		Headers:       redact.Headers(exchange.RequestHeaders),
+		Body:          string(redact.JSON(exchange.RequestBody)),
+		StatusCode:    exchange.StatusCode,
+		ResponseBody:  string(redact.JSON(exchange.ResponseBody)),
+		LatencyMillis: exchange.Latency.Milliseconds(),
+		CreatedAt:     time.Now().Add(-exchange.Latency),
+	}
+
+	var ack becknAck
+	if err := json.Unmarshal(exchange.ResponseBody, &ack); err == nil {
+		message.Ack = ack.Message.Ack.Status
+		if ack.Error != nil && ack.Error.Message != "" {
+			message.Error = fmt.Sprintf("%s: %s", ack.Error.Code, ack.Error.Message)
+		}
+	}
+
+	if exchange.Err != nil {
+		message.Error = exchange.Err.Error()
+	}
+
+	return message
+}
+
+// GetTransactionTimeline returns all the archived messages of a transaction, in the order they were exchanged
+func (a *Archive) GetTransactionTimeline(transactionID string) (*adminPayload.TransactionTimelineResponse, error) {
+	logrus.Infof("[Request]: Received request to get the timeline of transaction: %s", transactionID)
+
+	messages, err := a.clients.MessageArchiveClient.ListMessages(bson.D{{Key: "transaction_id", Value: transactionID}})
+	if err != nil {
+		logrus.Errorf("Failed to list messages of transaction %s, %v", transactionID, err)
+		return nil, fmt.Errorf("failed to list messages of transaction %s, %v", transactionID, err)
+	}
+
+	response := &adminPayload.TransactionTimelineResponse{
+		TransactionID: transactionID,
+		Messages:      []adminPayload.TransactionMessage{},
+	}
+
+	for _, message := range messages {
+		response.Messages = append(response.Messages, adminPayload.TransactionMessage{
+			Direction:     message.Direction,
+			Action:        message.Action,
+			MessageID:     message.MessageID,
+			BapID:         message.BapID,
+			Method:        message.Method,
+			URL:           message.URL,
+			Headers:       redact.Headers(message.Headers),
+			Body:          getRawJSON(message.Body),
+			StatusCode:    message.StatusCode,
+			ResponseBody:  getRawJSON(message.ResponseBody),
+			Ack:           message.Ack,
+			Error:         message.Error,
+			LatencyMillis: message.LatencyMillis,
+			CreatedAt:     message.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// getRawJSON renders a body as is when it is valid json, or else as a json string
+func getRawJSON(body string) json.RawMessage {
+	if body == "" {
+		return nil
+	}
+
+	if json.Valid([]byte(body)) {
+		return json.RawMessage(body)
+	}
+
+	raw, _ := json.Marshal(body)
+	return raw
+}
@@ -0,0 +1,63 @@
+package archive
+
+import (
+	"net/http"
+	"testing"
+
+	apiclient "github.com/ONEST-Network/Job-Manager-Adapter/pkg/api-client"
+	dbMessageArchive "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/message-archive"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/redact"
+)
+
+func TestGetMessage(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Authorization", `Signature keyId="bpp|key|ed25519",signature="c2lnbmF0dXJl"`)
+	headers.Set("X-Gateway-Authorization", `Signature keyId="gateway|key|ed25519"`)
+	headers.Set("Content-Type", "application/json")
+
+	exchange := &apiclient.Exchange{
+		Method:         http.MethodPost,
+		URL:            "https://bap.example/on_search",
+		RequestHeaders: headers,
+		RequestBody:    []byte(`{"context":{"action":"on_search","transaction_id":"t1","message_id":"m1"}}`),
+		StatusCode:     http.StatusOK,
+		ResponseBody:   []byte(`{"message":{"ack":{"status":"ACK"}}}`),
+	}
+
+	message := getMessage(dbMessageArchive.DirectionOutbound, exchange)
+	if message == nil {
+		t.Fatal("getMessage() = nil, want the archived message")
+	}
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "Authorization", want: redact.Mask(headers.Get("Authorization"))},
+		{header: "X-Gateway-Authorization", want: redact.Mask(headers.Get("X-Gateway-Authorization"))},
+		{header: "Content-Type", want: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := http.Header(message.Headers).Get(tt.header); got != tt.want {
+				t.Fatalf("Headers.Get(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+
+	if got := exchange.RequestHeaders.Get("Authorization"); got != headers.Get("Authorization") {
+		t.Fatalf("exchange Authorization header = %s, want it unchanged", got)
+	}
+	if message.Ack != "ACK" {
+		t.Fatalf("Ack = %s, want ACK", message.Ack)
+	}
+}
+
+func TestGetMessageNotBeckn(t *testing.T) {
+	exchange := &apiclient.Exchange{RequestBody: []byte(`{"job_id":"j1"}`)}
+
+	if message := getMessage(dbMessageArchive.DirectionInbound, exchange); message != nil {
+		t.Fatalf("getMessage() = %+v, want nil", message)
+	}
+}
@@ -0,0 +1,247 @@
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	auditPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
+)
+
+type Interface interface {
+	Record(source Source, action dbAudit.Action, target dbAudit.Target, before, after interface{})
+	ListAuditEntries(businessID string, payload *auditPayload.ListAuditEntriesRequest) ([]dbAudit.AuditEntry, error)
+}
+
+// Source represents who performed the audited operations, and the request they were performed for
+type Source struct {
+	Actor         dbAudit.Actor
+	CorrelationID string
+}
+
+// SystemSource returns the source of the operations performed by a background task of the adapter
+func SystemSource(task string) Source {
+	return Source{
+		Actor: dbAudit.Actor{
+			Type: dbAudit.ActorTypeSystem,
+			ID:   task,
+		},
+	}
+}
+
+// BAPSource returns the source of the operations performed for a beckn request
+func BAPSource(bapID, messageID string) Source {
+	return Source{
+		Actor: dbAudit.Actor{
+			Type: dbAudit.ActorTypeBAP,
+			ID:   bapID,
+		},
+		CorrelationID: messageID,
+	}
+}
+
+type Audit struct {
+	clients *clients.Clients
+}
+
+func NewAudit(clients *clients.Clients) Interface {
+	return &Audit{
+		clients: clients,
+	}
+}
+
+const (
+	defaultAuditEntriesLimit = 100
+	maxAuditEntriesLimit     = 1000
+)
+
+// redactedFields are the fields holding the personal details of the applicants, their changes
+// are recorded without their values
+var redactedFields = []string{"applicant_details", "applicant_keys", "pii", "beckn_context"}
+
+// Record appends an audit entry for an operation with the changes between the documents before and after
+// it, either of which can be nil, the business of the target is resolved through its job if not given.
+// Recording is best effort, a failure is logged and doesn't fail the operation.
+func (a *Audit) Record(source Source, action dbAudit.Action, target dbAudit.Target, before, after interface{}) {
+	if target.BusinessID == "" && target.JobID != "" {
+		if job, err := a.clients.JobClient.GetJob(target.JobID); err != nil {
+			logrus.Errorf("[Audit]: Failed to get job %s of %s audit entry, %v", target.JobID, action, err)
+		} else {
+			target.BusinessID = job.Business.ID
+		}
+	}
+
+	changes, err := getChanges(before, after)
+	if err != nil {
+		logrus.Errorf("[Audit]: Failed to get the changes of %s audit entry, %v", action, err)
+	}
+
+	entry := &dbAudit.AuditEntry{
+		ID:            random.GetRandomString(12),
+		Actor:         source.Actor,
+		Action:        action,
+		Target:        target,
+		Changes:       changes,
+		CorrelationID: source.CorrelationID,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := a.clients.AuditClient.CreateAuditEntry(entry); err != nil {
+		logrus.Errorf("[Audit]: Failed to record %s audit entry by %s %s, %v", action, source.Actor.Type, source.Actor.ID, err)
+	}
+}
+
+// ListAuditEntries lists the latest audit entries of a business matching the filters, newest first
+func (a *Audit) ListAuditEntries(businessID string, payload *auditPayload.ListAuditEntriesRequest) ([]dbAudit.AuditEntry, error) {
+	logrus.Infof("[Request]: Received request to list audit entries of business: %s", businessID)
+
+	query := bson.D{{Key: "target.business_id", Value: businessID}}
+
+	for field, value := range map[string]string{
+		"actor.type":                string(payload.ActorType),
+		"actor.id":                  payload.ActorID,
+		"action":                    string(payload.Action),
+		"target.job_id":             payload.JobID,
+		"target.job_application_id": payload.JobApplicationID,
+		"correlation_id":            payload.CorrelationID,
+	} {
+		if value != "" {
+			query = append(query, bson.E{Key: field, Value: value})
+		}
+	}
+
+	createdAt := bson.D{}
+	if payload.From != nil {
+		createdAt = append(createdAt, bson.E{Key: "$gte", Value: *payload.From})
+	}
+	if payload.To != nil {
+		createdAt = append(createdAt, bson.E{Key: "$lt", Value: *payload.To})
+	}
+	if len(createdAt) > 0 {
+		query = append(query, bson.E{Key: "created_at", Value: createdAt})
+	}
+
+	limit := payload.Limit
+	if limit <= 0 {
+		limit = defaultAuditEntriesLimit
+	}
+	limit = min(limit, maxAuditEntriesLimit)
+
+	entries, err := a.clients.AuditClient.ListAuditEntries(query, limit)
+	if err != nil {
+		logrus.Errorf("Failed to list audit entries of business %s, %v", businessID, err)
+		return nil, fmt.Errorf("failed to list audit entries of business %s, %v", businessID, err)
+	}
+
+	return entries, nil
+}
+
+// getChanges returns the fields which differ between two documents, keyed by their dotted paths
+func getChanges(before, after interface{}) ([]dbAudit.Change, error) {
+	beforeFields, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []dbAudit.Change
+
+	for _, field := range fields {
+		beforeValue, afterValue := beforeFields[field], afterFields[field]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+
+		if isRedacted(field) {
+			if beforeValue != nil {
+				beforeValue = "[REDACTED]"
+			}
+			if afterValue != nil {
+				afterValue = "[REDACTED]"
+			}
+		}
+
+		changes = append(changes, dbAudit.Change{
+			Field:  field,
+			Before: beforeValue,
+			After:  afterValue,
+		})
+	}
+
+	return changes, nil
+}
+
+// flatten returns the fields of a document keyed by their dotted paths, the arrays are kept as values
+func flatten(document interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	if value := reflect.ValueOf(document); !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return fields, nil
+	}
+
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+
+	flattenInto(fields, "", m)
+
+	return fields, nil
+}
+
+func flattenInto(fields map[string]interface{}, prefix string, m bson.M) {
+	for key, value := range m {
+		if key == "_id" {
+			continue
+		}
+
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(bson.M); ok {
+			flattenInto(fields, key, nested)
+			continue
+		}
+
+		fields[key] = value
+	}
+}
+
+func isRedacted(field string) bool {
+	for _, redacted := range redactedFields {
+		if field == redacted || strings.HasPrefix(field, redacted+".") {
+			return true
+		}
+	}
+
+	return false
+}
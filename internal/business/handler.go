@@ -3,8 +3,11 @@ package business
 import (
 	"fmt"
 
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	auditDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	businessDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
+	ratingDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/rating"
 	businessPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/business"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,15 +16,19 @@ import (
 type Interface interface {
 	AddBusiness(business *businessPayload.AddBusinessRequest) error
 	ListJobs(businessID string) ([]businessPayload.ListJobsResponse, error)
+	GetAnalytics(businessID string) (*businessPayload.GetAnalyticsResponse, error)
+	GetCandidateApplications(businessID, profileID string) ([]businessPayload.CandidateApplication, error)
 }
 
 type Business struct {
 	clients *clients.Clients
+	source  audit.Source
 }
 
-func NewBusiness(clients *clients.Clients) Interface {
+func NewBusiness(clients *clients.Clients, source audit.Source) Interface {
 	return &Business{
 		clients: clients,
+		source:  source,
 	}
 }
 
@@ -49,6 +56,8 @@ func (b *Business) AddBusiness(payload *businessPayload.AddBusinessRequest) erro
 		GSTIndexNumber: payload.GSTIndexNumber,
 		Location:       payload.Location,
 		Industry:       payload.Industry,
+		Support:        payload.Support,
+		ConsentTerms:   payload.ConsentTerms,
 	}
 
 	if err := b.clients.BusinessClient.CreateBusiness(business); err != nil {
@@ -56,6 +65,8 @@ func (b *Business) AddBusiness(payload *businessPayload.AddBusinessRequest) erro
 		return fmt.Errorf("failed to create %s business, %v", payload.ID, err)
 	}
 
+	audit.NewAudit(b.clients).Record(b.source, auditDb.ActionBusinessCreated, auditDb.Target{BusinessID: business.ID}, nil, business)
+
 	return nil
 }
 
@@ -73,19 +84,132 @@ func (b *Business) ListJobs(businessID string) ([]businessPayload.ListJobsRespon
 	var listJobsResponse []businessPayload.ListJobsResponse
 	for _, job := range jobs {
 		listJobsResponse = append(listJobsResponse, businessPayload.ListJobsResponse{
-			ID:             job.ID,
-			Name:           job.Name,
-			Description:    job.Description,
-			Type:           job.Type,
-			Vacancies:      job.Vacancies,
-			SalaryRange:    job.SalaryRange,
-			ApplicationIDs: job.ApplicationIDs,
-			WorkHours:      job.WorkHours,
-			WorkDays:       job.WorkDays,
-			Eligibility:    job.Eligibility,
-			Location:       job.Location,
+			ID:                 job.ID,
+			Name:               job.Name,
+			Description:        job.Description,
+			Type:               job.Type,
+			Openings:           job.Openings,
+			Hired:              job.Hired,
+			RemainingVacancies: job.RemainingVacancies(),
+			ApplicationCap:     job.ApplicationCap,
+			Applications:       job.Applications,
+			AvailableSlots:     job.AvailableSlots(),
+			Waitlist:           job.Waitlist,
+			SalaryRange:        job.SalaryRange,
+			ApplicationIDs:     job.ApplicationIDs,
+			WorkHours:          job.WorkHours,
+			WorkDays:           job.WorkDays,
+			Eligibility:        job.Eligibility,
+			Location:           job.Location,
 		})
 	}
 
 	return listJobsResponse, nil
 }
+
+func (b *Business) GetAnalytics(businessID string) (*businessPayload.GetAnalyticsResponse, error) {
+	logrus.Infof("[Request]: Received request to get analytics for business: %s", businessID)
+
+	if _, err := b.clients.BusinessClient.GetBusiness(businessID); err != nil {
+		logrus.Errorf("Failed to get business %s, %v", businessID, err)
+		return nil, fmt.Errorf("failed to get business %s, %v", businessID, err)
+	}
+
+	var (
+		response = &businessPayload.GetAnalyticsResponse{}
+		query    = bson.D{{Key: "business_id", Value: businessID}}
+	)
+
+	businessRatings, err := b.clients.RatingClient.AggregateRatings(ratingDb.CategoryProvider, "business_id", query)
+	if err != nil {
+		logrus.Errorf("Failed to aggregate ratings for business %s, %v", businessID, err)
+		return nil, fmt.Errorf("failed to aggregate ratings for business, %v", err)
+	}
+
+	if len(businessRatings) > 0 {
+		response.Rating.Average = businessRatings[0].Average
+		response.Rating.Count = businessRatings[0].Count
+	}
+
+	response.Rating.Distribution, err = b.clients.RatingClient.GetRatingDistribution(append(query, bson.E{Key: "category", Value: ratingDb.CategoryProvider}))
+	if err != nil {
+		logrus.Errorf("Failed to get rating distribution for business %s, %v", businessID, err)
+		return nil, fmt.Errorf("failed to get rating distribution for business, %v", err)
+	}
+
+	jobRatings, err := b.clients.RatingClient.AggregateRatings(ratingDb.CategoryItem, "job_id", query)
+	if err != nil {
+		logrus.Errorf("Failed to aggregate job ratings for business %s, %v", businessID, err)
+		return nil, fmt.Errorf("failed to aggregate job ratings for business, %v", err)
+	}
+
+	for _, jobRating := range jobRatings {
+		var name string
+		if job, err := b.clients.JobClient.GetJob(jobRating.ID); err == nil {
+			name = job.Name
+		}
+
+		response.JobRatings = append(response.JobRatings, businessPayload.JobRating{
+			JobID:   jobRating.ID,
+			Name:    name,
+			Average: jobRating.Average,
+			Count:   jobRating.Count,
+		})
+	}
+
+	return response, nil
+}
+
+// GetCandidateApplications lists the applications of a candidate to the jobs of the business, a business
+// can't see the candidate's applications to other businesses
+func (b *Business) GetCandidateApplications(businessID, profileID string) ([]businessPayload.CandidateApplication, error) {
+	logrus.Infof("[Request]: Received request to get applications of candidate %s for business: %s", profileID, businessID)
+
+	if _, err := b.clients.ApplicantProfileClient.GetApplicantProfile(profileID); err != nil {
+		logrus.Errorf("Failed to get applicant profile %s, %v", profileID, err)
+		return nil, fmt.Errorf("failed to get applicant profile %s, %v", profileID, err)
+	}
+
+	jobs, err := b.clients.JobClient.ListJobs(bson.D{{Key: "business.id", Value: businessID}})
+	if err != nil {
+		logrus.Errorf("Failed to get jobs for business %s, %v", businessID, err)
+		return nil, fmt.Errorf("failed to get jobs for business, %v", err)
+	}
+
+	var (
+		jobIDs   []string
+		jobNames = make(map[string]string)
+	)
+
+	for _, job := range jobs {
+		jobIDs = append(jobIDs, job.ID)
+		jobNames[job.ID] = job.Name
+	}
+
+	if len(jobIDs) == 0 {
+		return nil, nil
+	}
+
+	jobApplications, err := b.clients.JobApplicationClient.ListJobApplication(bson.D{
+		{Key: "profile_id", Value: profileID},
+		{Key: "job_id", Value: bson.D{{Key: "$in", Value: jobIDs}}},
+	})
+	if err != nil {
+		logrus.Errorf("Failed to list applications of candidate %s, %v", profileID, err)
+		return nil, fmt.Errorf("failed to list applications of candidate, %v", err)
+	}
+
+	var candidateApplications []businessPayload.CandidateApplication
+	for _, jobApplication := range jobApplications {
+		candidateApplications = append(candidateApplications, businessPayload.CandidateApplication{
+			ID:        jobApplication.ID,
+			JobID:     jobApplication.JobID,
+			JobName:   jobNames[jobApplication.JobID],
+			Status:    jobApplication.Status,
+			CreatedAt: jobApplication.CreatedAt,
+			UpdatedAt: jobApplication.UpdatedAt,
+		})
+	}
+
+	return candidateApplications, nil
+}
@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
+	healthPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/health"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/version"
+)
+
+const checkTimeout = 5 * time.Second
+
+type Interface interface {
+	Liveness() *healthPayload.HealthResponse
+	Readiness(ctx context.Context) *healthPayload.HealthResponse
+}
+
+type Health struct{}
+
+func NewHealth() Interface {
+	return &Health{}
+}
+
+// Liveness reports that the adapter is up, it doesn't check the dependencies so a broken dependency
+// doesn't get the adapter restarted
+func (h *Health) Liveness() *healthPayload.HealthResponse {
+	return &healthPayload.HealthResponse{
+		Status:  healthPayload.StatusUp,
+		Version: version.Get(),
+	}
+}
+
+// Readiness reports whether the adapter can serve requests, along with the status of each of its dependencies
+func (h *Health) Readiness(ctx context.Context) *healthPayload.HealthResponse {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	response := &healthPayload.HealthResponse{
+		Status:  healthPayload.StatusUp,
+		Version: version.Get(),
+		Checks: map[string]healthPayload.Check{
+			"mongodb":    check(func() (interface{}, error) { return nil, database.Ping(ctx) }),
+			"indexes":    check(func() (interface{}, error) { return checkIndexes(ctx) }),
+			"async":      check(checkAsyncBacklog),
+			"encryption": checkEncryption(),
+		},
+	}
+
+	for name, check := range response.Checks {
+		if check.Status == healthPayload.StatusDown {
+			logrus.Warnf("[Health]: %s check failed, %s", name, check.Error)
+			response.Status = healthPayload.StatusDown
+		}
+	}
+
+	return response
+}
+
+// check runs a dependency check, timing it
+func check(run func() (interface{}, error)) healthPayload.Check {
+	start := time.Now()
+
+	details, err := run()
+
+	result := healthPayload.Check{
+		Status:        healthPayload.StatusUp,
+		LatencyMillis: time.Since(start).Milliseconds(),
+		Details:       details,
+	}
+
+	if err != nil {
+		result.Status = healthPayload.StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func checkIndexes(ctx context.Context) (interface{}, error) {
+	missing, err := database.MissingIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		return map[string]interface{}{"missing": missing}, fmt.Errorf("%d indexes missing", len(missing))
+	}
+
+	return nil, nil
+}
+
+func checkAsyncBacklog() (interface{}, error) {
+	var (
+		backlog = metrics.AsyncBacklog()
+		limit   = config.Config.ReadinessMaxAsyncBacklog
+		details = map[string]int64{"backlog": backlog, "limit": limit}
+	)
+
+	if limit > 0 && backlog > limit {
+		return details, fmt.Errorf("backlog of %d requests exceeds the limit of %d", backlog, limit)
+	}
+
+	return details, nil
+}
+
+// checkEncryption verifies the keys of the key file, when one is configured
+func checkEncryption() healthPayload.Check {
+	if config.Config.EncryptionKeyFile == "" {
+		return healthPayload.Check{Status: healthPayload.StatusDisabled}
+	}
+
+	return check(func() (interface{}, error) {
+		return map[string]string{"activeKey": encryption.ActiveKeyID()}, encryption.Check()
+	})
+}
@@ -2,8 +2,15 @@ package jobapplication
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/onest"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/async"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	auditDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
 	jobApplicationPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/job-application"
 	"github.com/sirupsen/logrus"
@@ -12,15 +19,19 @@ import (
 
 type Interface interface {
 	UpdateJobApplicationStatus(applicationId string, payload *jobApplicationPayload.UpdateJobApplicationStatusRequest) error
+	ExtendOffer(applicationId string, payload *jobApplicationPayload.ExtendOfferRequest) error
+	ExpireOffers() error
 }
 
 type JobApplication struct {
 	clients *clients.Clients
+	source  audit.Source
 }
 
-func NewJobApplication(clients *clients.Clients) Interface {
+func NewJobApplication(clients *clients.Clients, source audit.Source) Interface {
 	return &JobApplication{
 		clients: clients,
+		source:  source,
 	}
 }
 
@@ -33,19 +44,211 @@ func (j *JobApplication) UpdateJobApplicationStatus(applicationId string, payloa
 		return err
 	}
 
+	jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(applicationId)
+	if err != nil {
+		logrus.Errorf("Failed to get job application %s, %v", applicationId, err)
+		return fmt.Errorf("failed to get job application %s, %v", applicationId, err)
+	}
+
+	if !canUpdateStatus(jobApplication.Status, jobApplicationStatus) {
+		logrus.Errorf("Cannot update job application %s status from %s to %s", applicationId, jobApplication.Status, jobApplicationStatus)
+		return fmt.Errorf("cannot update job application status from %s to %s", jobApplication.Status, jobApplicationStatus)
+	}
+
 	var (
-		query  = bson.D{{Key: "id", Value: applicationId}}
-		update = bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: jobApplicationStatus}}}}
+		query = bson.D{
+			{Key: "id", Value: applicationId},
+			{Key: "status", Value: jobApplication.Status},
+		}
+		update = bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: jobApplicationStatus},
+			{Key: "updated_at", Value: time.Now()},
+		}}}
 	)
 
-	if err := j.clients.JobApplicationClient.UpdateJobApplication(query, update); err != nil {
+	updated, err := j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, update)
+	if err != nil {
 		logrus.Errorf("Failed to update job application %s status, %v", applicationId, err)
 		return err
 	}
 
+	audit.NewAudit(j.clients).Record(j.source, auditDb.ActionJobApplicationStatus, auditDb.Target{JobID: jobApplication.JobID, JobApplicationID: applicationId}, jobApplication, updated)
+
+	// a rejected applicant frees the application slot for the waitlist
+	if err := onest.NewOnestClient(j.clients).UpdateJobCounters(jobApplication.JobID, jobApplication.Status, jobApplicationStatus); err != nil {
+		logrus.Errorf("Failed to update job %s counters, %v", jobApplication.JobID, err)
+	}
+
+	return nil
+}
+
+// ExtendOffer extends a new offer to the applicant, or revises the offer already extended
+func (j *JobApplication) ExtendOffer(applicationId string, payload *jobApplicationPayload.ExtendOfferRequest) error {
+	logrus.Infof("[Request]: Received request to extend an offer for job application %s", applicationId)
+
+	if payload.Salary <= 0 {
+		return fmt.Errorf("invalid offered salary %d", payload.Salary)
+	}
+
+	if payload.JoiningDate.IsZero() {
+		return fmt.Errorf("joining date is required")
+	}
+
+	jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(applicationId)
+	if err != nil {
+		logrus.Errorf("Failed to get job application %s, %v", applicationId, err)
+		return fmt.Errorf("failed to get job application %s, %v", applicationId, err)
+	}
+
+	if !canExtendOffer(jobApplication.Status) {
+		logrus.Errorf("Cannot extend an offer for job application %s in %s status", applicationId, jobApplication.Status)
+		return fmt.Errorf("cannot extend an offer for job application in %s status", jobApplication.Status)
+	}
+
+	var (
+		now       = time.Now()
+		expiresAt = now.Add(config.Config.OfferValidity)
+		offer     = &jobapplication.Offer{
+			Salary:      payload.Salary,
+			JoiningDate: payload.JoiningDate,
+			Location:    payload.Location,
+			Status:      jobapplication.OfferStatusExtended,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	)
+
+	if payload.ExpiresAt != nil {
+		if !payload.ExpiresAt.After(now) {
+			return fmt.Errorf("offer expiry %s is in the past", payload.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		expiresAt = *payload.ExpiresAt
+	}
+	offer.ExpiresAt = expiresAt
+
+	// a revised offer keeps the creation time of the first offer
+	if jobApplication.Offer != nil {
+		offer.Revision = jobApplication.Offer.Revision + 1
+		offer.CreatedAt = jobApplication.Offer.CreatedAt
+	}
+
+	var (
+		query = bson.D{
+			{Key: "id", Value: applicationId},
+			{Key: "status", Value: jobApplication.Status},
+		}
+		update = bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: jobapplication.JobApplicationStatusOfferExtended},
+			{Key: "offer", Value: offer},
+			{Key: "updated_at", Value: now},
+		}}}
+	)
+
+	updated, err := j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, update)
+	if err != nil {
+		logrus.Errorf("Failed to extend offer for job application %s, %v", applicationId, err)
+		return fmt.Errorf("failed to extend offer for job application %s, %v", applicationId, err)
+	}
+
+	audit.NewAudit(j.clients).Record(j.source, auditDb.ActionOfferExtended, auditDb.Target{JobID: jobApplication.JobID, JobApplicationID: applicationId}, jobApplication, updated)
+
+	async.Go("offer notification of job application "+applicationId, func() {
+		if err := onest.NewOnestClient(j.clients).NotifyJobApplicationUpdate(updated); err != nil {
+			logrus.Errorf("Failed to notify the offer of job application %s, %v", applicationId, err)
+		}
+	})
+
+	return nil
+}
+
+// ExpireOffers marks all the extended offers past their expiry as expired
+func (j *JobApplication) ExpireOffers() error {
+	var (
+		now   = time.Now()
+		query = bson.D{
+			{Key: "status", Value: jobapplication.JobApplicationStatusOfferExtended},
+			{Key: "offer.expires_at", Value: bson.D{{Key: "$lte", Value: now}}},
+		}
+		update = bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: jobapplication.JobApplicationStatusOfferExpired},
+			{Key: "offer.status", Value: jobapplication.OfferStatusExpired},
+			{Key: "offer.updated_at", Value: now},
+			{Key: "updated_at", Value: now},
+		}}}
+	)
+
+	jobApplications, err := j.clients.JobApplicationClient.ListJobApplication(query)
+	if err != nil {
+		return fmt.Errorf("failed to list expired job offers, %v", err)
+	}
+
+	var (
+		onest   = onest.NewOnestClient(j.clients)
+		expired int
+	)
+
+	// offers are expired one at a time, so the application slots they free go to the waitlist
+	for _, jobApplication := range jobApplications {
+		query := append(bson.D{{Key: "id", Value: jobApplication.ID}}, query...)
+
+		updated, err := j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, update)
+		if err != nil {
+			logrus.Errorf("Failed to expire the offer of job application %s, %v", jobApplication.ID, err)
+			continue
+		}
+		expired++
+
+		audit.NewAudit(j.clients).Record(j.source, auditDb.ActionOfferExpired, auditDb.Target{JobID: jobApplication.JobID, JobApplicationID: jobApplication.ID}, jobApplication, updated)
+
+		if err := onest.UpdateJobCounters(jobApplication.JobID, jobApplication.Status, jobapplication.JobApplicationStatusOfferExpired); err != nil {
+			logrus.Errorf("Failed to update job %s counters, %v", jobApplication.JobID, err)
+		}
+	}
+
+	if expired > 0 {
+		logrus.Infof("Expired %d unanswered job offers", expired)
+	}
+
 	return nil
 }
 
+// statusTransitions are the job application status updates an employer can make, the waitlisted
+// applications take a slot through the waitlist, the offers are extended through ExtendOffer and
+// accepted or rejected by the applicants
+var statusTransitions = map[jobapplication.JobApplicationStatus][]jobapplication.JobApplicationStatus{
+	jobapplication.JobApplicationStatusApplicationAccepted: {
+		jobapplication.JobApplicationStatusAssessmentInProgress,
+		jobapplication.JobApplicationStatusApplicationRejected,
+	},
+	jobapplication.JobApplicationStatusAssessmentInProgress: {
+		jobapplication.JobApplicationStatusApplicationRejected,
+	},
+	jobapplication.JobApplicationStatusOfferExtended: {
+		jobapplication.JobApplicationStatusApplicationRejected,
+	},
+	jobapplication.JobApplicationStatusWaitlisted: {
+		jobapplication.JobApplicationStatusApplicationRejected,
+	},
+}
+
+// canUpdateStatus reports whether an employer can update a job application from a status to another
+func canUpdateStatus(from, to jobapplication.JobApplicationStatus) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// canExtendOffer reports whether an offer can be extended, or revised, for a job application in the given status,
+// only the active applications hold a slot of the job, the rejected and expired offers are final
+func canExtendOffer(status jobapplication.JobApplicationStatus) bool {
+	switch status {
+	case jobapplication.JobApplicationStatusApplicationAccepted,
+		jobapplication.JobApplicationStatusAssessmentInProgress,
+		jobapplication.JobApplicationStatusOfferExtended:
+		return true
+	default:
+		return false
+	}
+}
+
 func getJobApplicationStatusEnum(jobApplicationStatus string) (jobapplication.JobApplicationStatus, error) {
 	switch jobApplicationStatus {
 	case string(jobapplication.JobApplicationStatusApplicationAccepted):
@@ -0,0 +1,116 @@
+package jobapplication
+
+import (
+	"testing"
+
+	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+)
+
+// TestTerminalStatuses checks the terminal statuses purged after their retention can't be changed by
+// the employers anymore
+func TestTerminalStatuses(t *testing.T) {
+	allStatuses := []jobapplication.JobApplicationStatus{
+		jobapplication.JobApplicationStatusApplicationAccepted,
+		jobapplication.JobApplicationStatusApplicationRejected,
+		jobapplication.JobApplicationStatusAssessmentInProgress,
+		jobapplication.JobApplicationStatusOfferRejected,
+		jobapplication.JobApplicationStatusOfferAccepted,
+		jobapplication.JobApplicationStatusOfferExtended,
+		jobapplication.JobApplicationStatusOfferExpired,
+		jobapplication.JobApplicationStatusCancelled,
+		jobapplication.JobApplicationStatusJobClosed,
+		jobapplication.JobApplicationStatusWaitlisted,
+	}
+
+	for _, status := range jobapplication.TerminalStatuses {
+		t.Run(string(status), func(t *testing.T) {
+			if canExtendOffer(status) {
+				t.Fatalf("canExtendOffer(%s) = true for a terminal status", status)
+			}
+
+			for _, to := range allStatuses {
+				if canUpdateStatus(status, to) {
+					t.Fatalf("canUpdateStatus(%s, %s) = true for a terminal status", status, to)
+				}
+			}
+		})
+	}
+}
+
+func TestCanUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		from jobapplication.JobApplicationStatus
+		to   jobapplication.JobApplicationStatus
+		want bool
+	}{
+		{
+			name: "accepted to assessment",
+			from: jobapplication.JobApplicationStatusApplicationAccepted,
+			to:   jobapplication.JobApplicationStatusAssessmentInProgress,
+			want: true,
+		},
+		{
+			name: "accepted to rejected",
+			from: jobapplication.JobApplicationStatusApplicationAccepted,
+			to:   jobapplication.JobApplicationStatusApplicationRejected,
+			want: true,
+		},
+		{
+			name: "assessment to rejected",
+			from: jobapplication.JobApplicationStatusAssessmentInProgress,
+			to:   jobapplication.JobApplicationStatusApplicationRejected,
+			want: true,
+		},
+		{
+			name: "offer extended to rejected",
+			from: jobapplication.JobApplicationStatusOfferExtended,
+			to:   jobapplication.JobApplicationStatusApplicationRejected,
+			want: true,
+		},
+		{
+			name: "waitlisted to rejected",
+			from: jobapplication.JobApplicationStatusWaitlisted,
+			to:   jobapplication.JobApplicationStatusApplicationRejected,
+			want: true,
+		},
+		{
+			name: "assessment back to accepted",
+			from: jobapplication.JobApplicationStatusAssessmentInProgress,
+			to:   jobapplication.JobApplicationStatusApplicationAccepted,
+			want: false,
+		},
+		{
+			name: "waitlisted to accepted, through the waitlist only",
+			from: jobapplication.JobApplicationStatusWaitlisted,
+			to:   jobapplication.JobApplicationStatusApplicationAccepted,
+			want: false,
+		},
+		{
+			name: "accepted to offer extended, through ExtendOffer only",
+			from: jobapplication.JobApplicationStatusApplicationAccepted,
+			to:   jobapplication.JobApplicationStatusOfferExtended,
+			want: false,
+		},
+		{
+			name: "offer extended to accepted, by the applicant only",
+			from: jobapplication.JobApplicationStatusOfferExtended,
+			to:   jobapplication.JobApplicationStatusOfferAccepted,
+			want: false,
+		},
+		{
+			name: "same status",
+			from: jobapplication.JobApplicationStatusApplicationAccepted,
+			to:   jobapplication.JobApplicationStatusApplicationAccepted,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canUpdateStatus(tt.from, tt.to); got != tt.want {
+				t.Fatalf("canUpdateStatus(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
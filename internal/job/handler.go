@@ -2,11 +2,22 @@ package job
 
 import (
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
 
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/onest"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/async"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	auditDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	jobDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
+	jobApplicationDb "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
 	jobPayload "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/job"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
 )
@@ -14,38 +25,60 @@ import (
 type Interface interface {
 	CreateJob(payload *jobPayload.CreateJobRequest) error
 	GetJobApplications(jobID string) ([]jobPayload.GetJobApplicationsResponse, error)
+	CloseJob(jobID string, payload *jobPayload.CloseJobRequest) (*jobPayload.CloseJobResponse, error)
+	ReconcileJobCounters() error
+	MigrateLegacyJobs() error
+	RefreshMetrics() error
 }
 
 type Job struct {
 	clients *clients.Clients
+	source  audit.Source
 }
 
-func NewJob(clients *clients.Clients) Interface {
+func NewJob(clients *clients.Clients, source audit.Source) Interface {
 	return &Job{
 		clients: clients,
+		source:  source,
 	}
 }
 
 func (j *Job) CreateJob(payload *jobPayload.CreateJobRequest) error {
 	logrus.Infof("[Request]: Received request to create a new job for business: %s", payload.BusinessID)
 
+	if payload.Openings == 0 && payload.Vacancies != 0 {
+		logrus.Warnf("[Request]: Received deprecated vacancies for a new job of business: %s, use openings instead", payload.BusinessID)
+		payload.Openings = payload.Vacancies
+	}
+
+	if payload.Openings <= 0 {
+		return fmt.Errorf("invalid number of openings %d", payload.Openings)
+	}
+
+	if payload.ApplicationCap < 0 {
+		return fmt.Errorf("invalid application cap %d", payload.ApplicationCap)
+	}
+
 	business, err := j.clients.BusinessClient.GetBusiness(payload.BusinessID)
 	if err != nil {
 		return fmt.Errorf("failed to get business with id %s, %v", payload.BusinessID, err)
 	}
 
 	var job = &jobDb.Job{
-		ID:          random.GetRandomString(7),
-		Name:        payload.Name,
-		Description: payload.Description,
-		Type:        payload.Type,
-		Vacancies:   payload.Vacancies,
-		SalaryRange: payload.SalaryRange,
-		WorkHours:   payload.WorkHours,
-		WorkDays:    payload.WorkDays,
-		Eligibility: payload.Eligibility,
-		Location:    payload.Location,
-		Business:    *business,
+		ID:             random.GetRandomString(7),
+		Name:           payload.Name,
+		Description:    payload.Description,
+		Type:           payload.Type,
+		Openings:       payload.Openings,
+		ApplicationCap: payload.ApplicationCap,
+		Waitlist:       payload.Waitlist,
+		SalaryRange:    payload.SalaryRange,
+		WorkHours:      payload.WorkHours,
+		WorkDays:       payload.WorkDays,
+		Eligibility:    payload.Eligibility,
+		Location:       payload.Location,
+		Business:       *business,
+		Status:         jobDb.JobStatusOpen,
 	}
 
 	if err := j.clients.JobClient.CreateJob(job); err != nil {
@@ -53,6 +86,8 @@ func (j *Job) CreateJob(payload *jobPayload.CreateJobRequest) error {
 		return err
 	}
 
+	audit.NewAudit(j.clients).Record(j.source, auditDb.ActionJobCreated, auditDb.Target{BusinessID: business.ID, JobID: job.ID}, nil, job)
+
 	return nil
 }
 
@@ -61,25 +96,290 @@ func (j *Job) GetJobApplications(jobID string) ([]jobPayload.GetJobApplicationsR
 
 	logrus.Infof("[Request]: Received request to get applications for %s job", jobID)
 
-	job, err := j.clients.JobClient.GetJob(jobID)
-	if err != nil {
+	if _, err := j.clients.JobClient.GetJob(jobID); err != nil {
 		logrus.Errorf("Failed to get job %s, %v", jobID, err)
 		return nil, fmt.Errorf("failed to get job %s, %v", jobID, err)
 	}
 
-	for _, applicationId := range job.ApplicationIDs {
-		jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(applicationId)
-		if err != nil {
-			logrus.Errorf("Failed to get job application %s, %v", applicationId, err)
-			return nil, fmt.Errorf("failed to get job application %s, %v", applicationId, err)
+	jobApplications, err := j.clients.JobApplicationClient.ListJobApplication(bson.D{{Key: "job_id", Value: jobID}})
+	if err != nil {
+		logrus.Errorf("Failed to list applications for job %s, %v", jobID, err)
+		return nil, fmt.Errorf("failed to list applications for job %s, %v", jobID, err)
+	}
+
+	for _, jobApplication := range jobApplications {
+		// the documents are shared with the employer only with the applicant's consent
+		if !jobApplication.Consent.Allows(jobApplicationDb.ConsentScopeDocuments) {
+			jobApplication.ApplicantDetails.Documents = jobApplicationDb.Documents{}
 		}
 
 		jobApplicationsResponse = append(jobApplicationsResponse, jobPayload.GetJobApplicationsResponse{
-			ID:               applicationId,
+			ID:               jobApplication.ID,
 			ApplicantDetails: jobApplication.ApplicantDetails,
 			Status:           jobApplication.Status,
+			Consent:          jobApplication.Consent,
+			ProfileID:        jobApplication.ProfileID,
+			Duplicate:        jobApplication.DuplicateOf != "",
+			DuplicateOf:      jobApplication.DuplicateOf,
 		})
 	}
 
 	return jobApplicationsResponse, nil
 }
+
+// CloseJob closes a filled or withdrawn job, all the active applications of the job are closed
+// and their BAPs are notified in the background
+func (j *Job) CloseJob(jobID string, payload *jobPayload.CloseJobRequest) (*jobPayload.CloseJobResponse, error) {
+	logrus.Infof("[Request]: Received request to close %s job", jobID)
+
+	if payload.Reason != jobDb.ClosureReasonFilled && payload.Reason != jobDb.ClosureReasonWithdrawn {
+		return nil, fmt.Errorf("invalid job closure reason %s", payload.Reason)
+	}
+
+	var (
+		now   = time.Now()
+		query = bson.D{
+			{Key: "id", Value: jobID},
+			{Key: "status", Value: bson.D{{Key: "$ne", Value: jobDb.JobStatusClosed}}},
+		}
+		update = bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: jobDb.JobStatusClosed},
+			{Key: "closure", Value: jobDb.Closure{
+				Reason:      payload.Reason,
+				Description: payload.Description,
+				ClosedAt:    now,
+			}},
+		}}}
+	)
+
+	job, err := j.clients.JobClient.UpdateJobAndReturnDocument(query, update)
+	if err != nil {
+		logrus.Errorf("Failed to close job %s, %v", jobID, err)
+		return nil, fmt.Errorf("failed to close job %s, it either doesn't exist or is already closed, %v", jobID, err)
+	}
+
+	var (
+		auditor = audit.NewAudit(j.clients)
+		target  = auditDb.Target{BusinessID: job.Business.ID, JobID: jobID}
+	)
+
+	auditor.Record(j.source, auditDb.ActionJobClosed, target, bson.M{"status": jobDb.JobStatusOpen}, bson.M{"status": job.Status, "closure": job.Closure})
+
+	jobApplications, err := j.clients.JobApplicationClient.ListJobApplication(bson.D{
+		{Key: "job_id", Value: jobID},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: jobApplicationDb.CancellableStatuses}}},
+	})
+	if err != nil {
+		logrus.Errorf("Failed to list active applications for job %s, %v", jobID, err)
+		return nil, fmt.Errorf("failed to list active applications for job %s, %v", jobID, err)
+	}
+
+	var (
+		closedJobApplications []*jobApplicationDb.JobApplication
+		releasedSlots         int
+	)
+
+	for _, jobApplication := range jobApplications {
+		var (
+			query = bson.D{
+				{Key: "id", Value: jobApplication.ID},
+				{Key: "status", Value: bson.D{{Key: "$in", Value: jobApplicationDb.CancellableStatuses}}},
+			}
+			update = bson.D{{Key: "$set", Value: bson.D{
+				{Key: "status", Value: jobApplicationDb.JobApplicationStatusJobClosed},
+				{Key: "cancellation", Value: jobApplicationDb.Cancellation{
+					ReasonID:    string(payload.Reason),
+					Reason:      payload.Description,
+					CancelledBy: jobApplicationDb.CancelledByEmployer,
+					CancelledAt: now,
+				}},
+				{Key: "updated_at", Value: now},
+			}}}
+		)
+
+		closedJobApplication, err := j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, update)
+		if err != nil {
+			logrus.Errorf("Failed to close job application %s, %v", jobApplication.ID, err)
+			continue
+		}
+
+		closedJobApplications = append(closedJobApplications, closedJobApplication)
+
+		target.JobApplicationID = jobApplication.ID
+		auditor.Record(j.source, auditDb.ActionJobApplicationClosed, target, jobApplication, closedJobApplication)
+
+		if slices.Contains(jobApplicationDb.ActiveStatuses, jobApplication.Status) {
+			releasedSlots++
+		}
+	}
+
+	if releasedSlots > 0 {
+		var (
+			query  = bson.D{{Key: "id", Value: jobID}}
+			update = bson.D{{Key: "$inc", Value: bson.D{{Key: "applications", Value: -releasedSlots}}}}
+		)
+
+		if err := j.clients.JobClient.UpdateJob(query, update); err != nil {
+			logrus.Errorf("Failed to update job %s counters, %v", jobID, err)
+		}
+	}
+
+	async.Go("closure notifications of job "+jobID, func() { j.notifyClosedJobApplications(closedJobApplications) })
+
+	return &jobPayload.CloseJobResponse{
+		ID:                 jobID,
+		ClosedApplications: len(closedJobApplications),
+	}, nil
+}
+
+// notifyClosedJobApplications sends an unsolicited on_cancel to the BAP of each closed job application,
+// in batches of the configured size to bound the number of concurrent callbacks
+func (j *Job) notifyClosedJobApplications(jobApplications []*jobApplicationDb.JobApplication) {
+	var (
+		onest     = onest.NewOnestClient(j.clients)
+		batchSize = max(config.Config.JobClosureBatchSize, 1)
+	)
+
+	for start := 0; start < len(jobApplications); start += batchSize {
+		var wg sync.WaitGroup
+
+		for _, jobApplication := range jobApplications[start:min(start+batchSize, len(jobApplications))] {
+			wg.Add(1)
+
+			go func(jobApplication *jobApplicationDb.JobApplication) {
+				defer wg.Done()
+
+				if err := onest.NotifyJobApplicationCancellation(jobApplication); err != nil {
+					logrus.Errorf("Failed to notify closure of job application %s, %v", jobApplication.ID, err)
+				}
+			}(jobApplication)
+		}
+
+		wg.Wait()
+	}
+}
+
+// ReconcileJobCounters recomputes the in-progress applications and the hires of every job from the
+// job application collection, and migrates the remaining vacancies of the older jobs to openings
+func (j *Job) ReconcileJobCounters() error {
+	return j.reconcileJobCounters(bson.D{})
+}
+
+// MigrateLegacyJobs migrates the remaining vacancies of the jobs created before the openings were tracked
+// to openings, along with their counters, it is run at startup as those jobs match no search until then
+func (j *Job) MigrateLegacyJobs() error {
+	return j.reconcileJobCounters(bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "openings", Value: bson.D{{Key: "$exists", Value: false}}}},
+		bson.D{{Key: "openings", Value: 0}},
+	}}})
+}
+
+// reconcileJobCounters reconciles the counters of the jobs matching the query
+func (j *Job) reconcileJobCounters(query bson.D) error {
+	jobs, err := j.clients.JobClient.ListJobs(query)
+	if err != nil {
+		return fmt.Errorf("failed to list jobs, %v", err)
+	}
+
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	countQuery := bson.D{}
+	if len(query) > 0 {
+		jobIDs := bson.A{}
+		for _, job := range jobs {
+			jobIDs = append(jobIDs, job.ID)
+		}
+		countQuery = bson.D{{Key: "job_id", Value: bson.D{{Key: "$in", Value: jobIDs}}}}
+	}
+
+	counts, err := j.clients.JobApplicationClient.CountJobApplicationsByStatus(countQuery)
+	if err != nil {
+		return fmt.Errorf("failed to count job applications, %v", err)
+	}
+
+	var (
+		applications = make(map[string]int)
+		hired        = make(map[string]int)
+	)
+
+	for _, count := range counts {
+		switch {
+		case slices.Contains(jobApplicationDb.ActiveStatuses, count.ID.Status):
+			applications[count.ID.JobID] += count.Count
+		case count.ID.Status == jobApplicationDb.JobApplicationStatusOfferAccepted:
+			hired[count.ID.JobID] += count.Count
+		}
+	}
+
+	var reconciled int
+
+	for _, job := range jobs {
+		var (
+			query = bson.D{{Key: "id", Value: job.ID}}
+			set   = bson.D{
+				{Key: "applications", Value: applications[job.ID]},
+				{Key: "hired", Value: hired[job.ID]},
+			}
+			update bson.D
+		)
+
+		// the jobs created before the openings were tracked have none, their remaining vacancies were
+		// decremented for every application taken, down to zero for the fully booked ones
+		if job.Openings == 0 {
+			openings := job.Vacancies + applications[job.ID] + hired[job.ID]
+			if openings == 0 {
+				logrus.Warnf("Failed to derive the openings of job %s, it has neither vacancies nor applications", job.ID)
+				continue
+			}
+
+			set = append(set, bson.E{Key: "openings", Value: openings})
+			update = append(update, bson.E{Key: "$unset", Value: bson.D{{Key: "vacancies", Value: ""}}})
+
+			logrus.Infof("Migrated job %s remaining vacancies %d to %d openings", job.ID, job.Vacancies, openings)
+		} else if job.Applications == applications[job.ID] && job.Hired == hired[job.ID] {
+			continue
+		}
+
+		logrus.Infof("Reconciling job %s counters, applications: %d -> %d, hired: %d -> %d",
+			job.ID, job.Applications, applications[job.ID], job.Hired, hired[job.ID])
+
+		update = append(bson.D{{Key: "$set", Value: set}}, update...)
+		reconciledJob, err := j.clients.JobClient.UpdateJobAndReturnDocument(query, update)
+		if err != nil {
+			logrus.Errorf("Failed to reconcile job %s counters, %v", job.ID, err)
+			continue
+		}
+		reconciled++
+
+		audit.NewAudit(j.clients).Record(j.source, auditDb.ActionJobCountersReconciled, auditDb.Target{BusinessID: job.Business.ID, JobID: job.ID}, job, reconciledJob)
+	}
+
+	logrus.Infof("Reconciled the counters of %d out of %d jobs", reconciled, len(jobs))
+
+	return nil
+}
+
+// RefreshMetrics updates the gauges of the open jobs and the job applications per status
+func (j *Job) RefreshMetrics() error {
+	openJobs, err := j.clients.JobClient.CountJobs(bson.D{{Key: "status", Value: bson.D{{Key: "$ne", Value: jobDb.JobStatusClosed}}}})
+	if err != nil {
+		return fmt.Errorf("failed to count open jobs, %v", err)
+	}
+
+	counts, err := j.clients.JobApplicationClient.CountJobApplicationsByStatus(bson.D{})
+	if err != nil {
+		return fmt.Errorf("failed to count job applications, %v", err)
+	}
+
+	jobApplications := make(map[string]int)
+	for _, count := range counts {
+		jobApplications[string(count.ID.Status)] += count.Count
+	}
+
+	metrics.SetOpenJobs(openJobs)
+	metrics.SetJobApplications(jobApplications)
+
+	return nil
+}
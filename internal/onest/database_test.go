@@ -0,0 +1,380 @@
+package onest
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	dbJob "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
+	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+)
+
+// collectionKey carries the collection of a test dao in its context, the test daos have no mongo collection
+// so the in-memory operator tells them apart by it
+type collectionKey struct{}
+
+// memoryOperator is an in-memory database operator for the tests, it supports the queries and the updates
+// made by the job slots and the waitlist, any other operation fails the test
+type memoryOperator struct {
+	database.MongoOperator
+
+	t           *testing.T
+	collections map[string][]bson.M
+}
+
+// recordingAPIClient acks every api call and records the urls called, for eg. the BAP callbacks
+type recordingAPIClient struct {
+	urls []string
+}
+
+func (c *recordingAPIClient) ApiCall(_ context.Context, _ interface{}, url string, _ interface{}, _ string) error {
+	c.urls = append(c.urls, url)
+	return nil
+}
+
+// newMemoryClients returns the clients of an in-memory database, which replaces the database operator
+// for the duration of the test
+func newMemoryClients(t *testing.T) (*clients.Clients, *memoryOperator, *recordingAPIClient) {
+	t.Helper()
+
+	var (
+		operator  = &memoryOperator{t: t, collections: map[string][]bson.M{}}
+		apiClient = &recordingAPIClient{}
+		previous  = database.Operator
+	)
+
+	database.Operator = operator
+	t.Cleanup(func() { database.Operator = previous })
+
+	collection := func(name string) context.Context {
+		return context.WithValue(context.Background(), collectionKey{}, name)
+	}
+
+	return &clients.Clients{
+		Context:              context.Background(),
+		ApiClient:            apiClient,
+		JobClient:            (&dbJob.Dao{}).WithContext(collection("job")),
+		JobApplicationClient: (&dbJobApplication.Dao{}).WithContext(collection("job-application")),
+		AuditClient:          (&dbAudit.Dao{}).WithContext(collection("audit")),
+	}, operator, apiClient
+}
+
+func (m *memoryOperator) Create(ctx context.Context, _ *mongo.Collection, document interface{}) (*mongo.InsertOneResult, error) {
+	name := m.getCollection(ctx)
+	m.collections[name] = append(m.collections[name], m.getDocument(document))
+
+	return &mongo.InsertOneResult{}, nil
+}
+
+func (m *memoryOperator) Get(ctx context.Context, _ *mongo.Collection, query bson.D) *mongo.SingleResult {
+	documents := m.find(ctx, query, nil)
+	if len(documents) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+
+	return mongo.NewSingleResultFromDocument(documents[0], nil, nil)
+}
+
+func (m *memoryOperator) List(ctx context.Context, _ *mongo.Collection, query bson.D, _ ...*options.FindOptions) (*mongo.Cursor, error) {
+	var documents []interface{}
+	for _, document := range m.find(ctx, query, nil) {
+		documents = append(documents, document)
+	}
+
+	return mongo.NewCursorFromDocuments(documents, nil, nil)
+}
+
+func (m *memoryOperator) Update(ctx context.Context, _ *mongo.Collection, query, update bson.D, _ ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	documents := m.find(ctx, query, nil)
+	if len(documents) == 0 {
+		return &mongo.UpdateResult{}, nil
+	}
+
+	m.update(documents[0], update)
+
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (m *memoryOperator) UpdateAndReturnDocument(ctx context.Context, _ *mongo.Collection, query, update bson.D,
+	opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	var sort interface{}
+	for _, opt := range opts {
+		if opt.Sort != nil {
+			sort = opt.Sort
+		}
+	}
+
+	documents := m.find(ctx, query, sort)
+	if len(documents) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+
+	m.update(documents[0], update)
+
+	return mongo.NewSingleResultFromDocument(documents[0], nil, nil)
+}
+
+// getJob returns a job as stored in the in-memory database
+func (m *memoryOperator) getJob(jobID string) *dbJob.Job {
+	m.t.Helper()
+
+	var job dbJob.Job
+	if err := m.Get(context.WithValue(context.Background(), collectionKey{}, "job"), nil, bson.D{{Key: "id", Value: jobID}}).Decode(&job); err != nil {
+		m.t.Fatalf("Failed to get job %s, %v", jobID, err)
+	}
+
+	return &job
+}
+
+// getJobApplication returns a job application as stored in the in-memory database
+func (m *memoryOperator) getJobApplication(jobApplicationID string) *dbJobApplication.JobApplication {
+	m.t.Helper()
+
+	var jobApplication dbJobApplication.JobApplication
+	if err := m.Get(context.WithValue(context.Background(), collectionKey{}, "job-application"), nil, bson.D{{Key: "id", Value: jobApplicationID}}).Decode(&jobApplication); err != nil {
+		m.t.Fatalf("Failed to get job application %s, %v", jobApplicationID, err)
+	}
+
+	return &jobApplication
+}
+
+func (m *memoryOperator) getCollection(ctx context.Context) string {
+	name, ok := ctx.Value(collectionKey{}).(string)
+	if !ok {
+		m.t.Fatalf("no collection found in the context of the database operation")
+	}
+
+	return name
+}
+
+// getDocument returns a document as it is stored in the database
+func (m *memoryOperator) getDocument(document interface{}) bson.M {
+	data, err := bson.Marshal(document)
+	if err != nil {
+		m.t.Fatalf("Failed to marshal %T document, %v", document, err)
+	}
+
+	var stored bson.M
+	if err := bson.Unmarshal(data, &stored); err != nil {
+		m.t.Fatalf("Failed to unmarshal %T document, %v", document, err)
+	}
+
+	return stored
+}
+
+// find returns the documents of the collection matching the query, ordered by a single field sort
+func (m *memoryOperator) find(ctx context.Context, query bson.D, sort interface{}) []bson.M {
+	var documents []bson.M
+
+	for _, document := range m.collections[m.getCollection(ctx)] {
+		if m.matches(document, query) {
+			documents = append(documents, document)
+		}
+	}
+
+	if sort != nil {
+		keys, ok := sort.(bson.D)
+		if !ok || len(keys) != 1 {
+			m.t.Fatalf("unsupported sort %v", sort)
+		}
+
+		slices.SortStableFunc(documents, func(a, b bson.M) int {
+			order := compare(normalize(getField(a, keys[0].Key)), normalize(getField(b, keys[0].Key)))
+			if keys[0].Value == -1 {
+				return -order
+			}
+			return order
+		})
+	}
+
+	return documents
+}
+
+// matches reports whether a document matches a query, the $expr of the jobs are matched through their
+// job counterparts, which the job tests check agree with them
+func (m *memoryOperator) matches(document bson.M, query bson.D) bool {
+	for _, condition := range query {
+		switch condition.Key {
+		case "$expr":
+			var job dbJob.Job
+			data, _ := bson.Marshal(document)
+			if err := bson.Unmarshal(data, &job); err != nil {
+				m.t.Fatalf("Failed to decode job of the $expr query, %v", err)
+			}
+
+			switch {
+			case reflect.DeepEqual(condition, dbJob.AvailableSlotsQuery()):
+				if job.AvailableSlots() == 0 {
+					return false
+				}
+			case reflect.DeepEqual(condition, dbJob.RemainingVacanciesQuery()):
+				if job.RemainingVacancies() == 0 {
+					return false
+				}
+			default:
+				m.t.Fatalf("unsupported $expr query %v", condition.Value)
+			}
+		case "$or":
+			var matched bool
+			for _, alternative := range condition.Value.(bson.A) {
+				if m.matches(document, alternative.(bson.D)) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		default:
+			if !m.matchesField(getField(document, condition.Key), condition.Value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (m *memoryOperator) matchesField(value, condition interface{}) bool {
+	operators, ok := condition.(bson.D)
+	if !ok {
+		return equals(value, condition)
+	}
+
+	for _, operator := range operators {
+		switch operator.Key {
+		case "$ne":
+			if equals(value, operator.Value) {
+				return false
+			}
+		case "$in":
+			if !slices.ContainsFunc(toSlice(operator.Value), func(candidate interface{}) bool { return equals(value, candidate) }) {
+				return false
+			}
+		case "$exists":
+			if (value != nil) != operator.Value.(bool) {
+				return false
+			}
+		default:
+			m.t.Fatalf("unsupported query operator %s", operator.Key)
+		}
+	}
+
+	return true
+}
+
+func (m *memoryOperator) update(document bson.M, update bson.D) {
+	for _, operator := range update {
+		for _, field := range operator.Value.(bson.D) {
+			switch operator.Key {
+			case "$set":
+				setField(document, field.Key, m.getValue(field.Value))
+			case "$inc":
+				setField(document, field.Key, normalize(getField(document, field.Key)).(int64)+normalize(field.Value).(int64))
+			default:
+				m.t.Fatalf("unsupported update operator %s", operator.Key)
+			}
+		}
+	}
+}
+
+// getValue returns a value as it is stored in the database
+func (m *memoryOperator) getValue(value interface{}) interface{} {
+	return m.getDocument(bson.M{"value": value})["value"]
+}
+
+// getField returns the value of a field of a document at a dotted path, or nil if it isn't set
+func getField(document bson.M, path string) interface{} {
+	var value interface{} = document
+
+	for _, segment := range strings.Split(path, ".") {
+		switch parent := value.(type) {
+		case bson.M:
+			value = parent[segment]
+		case bson.D:
+			value = parent.Map()[segment]
+		default:
+			return nil
+		}
+	}
+
+	return value
+}
+
+// setField sets a field of a document at a dotted path, creating its parent documents
+func setField(document bson.M, path string, value interface{}) {
+	segments := strings.Split(path, ".")
+
+	for _, segment := range segments[:len(segments)-1] {
+		parent, ok := document[segment].(bson.M)
+		if !ok {
+			parent = bson.M{}
+			document[segment] = parent
+		}
+		document = parent
+	}
+
+	document[segments[len(segments)-1]] = value
+}
+
+// equals compares a stored value to a query value, the arrays match any of their elements
+func equals(value, condition interface{}) bool {
+	if values, ok := value.(bson.A); ok {
+		return slices.ContainsFunc(values, func(element interface{}) bool { return equals(element, condition) })
+	}
+
+	return reflect.DeepEqual(normalize(value), normalize(condition))
+}
+
+// normalize returns the strings of the named string types, and the numbers and dates as int64
+func normalize(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.String:
+		return reflected.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflected.Int()
+	}
+
+	return value
+}
+
+func compare(a, b interface{}) int {
+	switch a := a.(type) {
+	case int64:
+		if b, ok := b.(int64); ok {
+			return cmp.Compare(a, b)
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return cmp.Compare(a, b)
+		}
+	}
+
+	return 0
+}
+
+func toSlice(value interface{}) []interface{} {
+	reflected := reflect.ValueOf(value)
+
+	values := make([]interface{}, reflected.Len())
+	for i := range values {
+		values[i] = reflected.Index(i).Interface()
+	}
+
+	return values
+}
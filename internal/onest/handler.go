@@ -2,19 +2,33 @@ package onest
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/builders/onest"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	dbApplicantProfile "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/applicant-profile"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	dbBusiness "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
 	dbInitJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/init-job-application"
+	dbJob "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
 	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	dbRating "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/rating"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/log"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
 
 	searchrequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/search/request"
 	searchrequestack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/search/request-ack"
@@ -39,6 +53,18 @@ import (
 	cancelrequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/cancel/request"
 	cancelrequestack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/cancel/request-ack"
 	cancelresponseack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/cancel/response-ack"
+
+	updaterequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/update/request"
+	updaterequestack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/update/request-ack"
+	updateresponseack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/update/response-ack"
+
+	ratingrequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/rating/request"
+	ratingrequestack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/rating/request-ack"
+	ratingresponseack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/rating/response-ack"
+
+	supportrequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/support/request"
+	supportrequestack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/support/request-ack"
+	supportresponseack "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/support/response-ack"
 )
 
 type Interface interface {
@@ -60,6 +86,21 @@ type Interface interface {
 	// cancel api handlers
 	WithdrawJobApplicationAck(body io.ReadCloser) (*cancelrequest.CancelRequest, *cancelrequestack.CancelRequestAck)
 	WithdrawJobApplication(payload *cancelrequest.CancelRequest)
+	// update api handlers
+	UpdateJobApplicationAck(body io.ReadCloser) (*updaterequest.UpdateRequest, *dbJobApplication.JobApplication, *updaterequestack.UpdateRequestAck)
+	UpdateJobApplication(payload *updaterequest.UpdateRequest, jobApplication *dbJobApplication.JobApplication)
+	// rating api handlers
+	SubmitRatingAck(body io.ReadCloser) (*ratingrequest.RatingRequest, *ratingrequestack.RatingRequestAck)
+	SubmitRating(payload *ratingrequest.RatingRequest)
+	// support api handlers
+	SendSupportAck(body io.ReadCloser) (*supportrequest.SupportRequest, *supportrequestack.SupportRequestAck)
+	SendSupport(payload *supportrequest.SupportRequest)
+	// unsolicited callbacks
+	NotifyJobApplicationCancellation(jobApplication *dbJobApplication.JobApplication) error
+	NotifyJobApplicationStatus(jobApplication *dbJobApplication.JobApplication) error
+	NotifyJobApplicationUpdate(jobApplication *dbJobApplication.JobApplication) error
+	// job counters
+	UpdateJobCounters(jobID string, from, to dbJobApplication.JobApplicationStatus) error
 }
 
 type Onest struct {
@@ -72,6 +113,11 @@ func NewOnestClient(clients *clients.Clients) Interface {
 	}
 }
 
+// logger returns the logger of the request being processed, carrying its beckn message
+func (j *Onest) logger() *logrus.Entry {
+	return log.WithContext(j.clients.Context)
+}
+
 func (j *Onest) SendJobsAck(body io.ReadCloser) (*searchrequest.SearchRequest, *searchrequestack.SearchRequestAck) {
 	var (
 		payload      searchrequest.SearchRequest
@@ -104,24 +150,24 @@ func (j *Onest) SendJobsAck(body io.ReadCloser) (*searchrequest.SearchRequest, *
 func (j *Onest) SendJobs(payload *searchrequest.SearchRequest) {
 	jobs, err := j.clients.JobClient.ListJobs(getSearchFilter(payload))
 	if err != nil {
-		logrus.Errorf("Failed to list jobs, %v", err)
+		j.logger().Errorf("Failed to list jobs, %v", err)
 		return
 	}
 
 	response, err := onest.BuildSearchJobsResponse(j.clients, payload, jobs)
 	if err != nil {
-		logrus.Errorf("Failed to build list jobs, %v", err)
+		j.logger().Errorf("Failed to build list jobs, %v", err)
 		return
 	}
 
 	var searchResponseAck searchresponseack.SearchResponseAck
-	if err := j.clients.ApiClient.ApiCall(response, payload.Context.BapURI+"/on_search", &searchResponseAck, "POST"); err != nil {
-		logrus.Errorf("Failed to send jobs search response, %v", err)
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_search", &searchResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send jobs search response, %v", err)
 		return
 	}
 
 	if searchResponseAck.Error.Message != "" {
-		logrus.Errorf("Received error while sending jobs, %v", searchResponseAck.Error.Message)
+		j.logger().Errorf("Received error while sending jobs, %v", searchResponseAck.Error.Message)
 		return
 	}
 }
@@ -166,7 +212,11 @@ func (j *Onest) SendJobFulfillmentAck(body io.ReadCloser) (*selectrequest.Select
 		return nil, getError("No job found for id: "+payload.Message.Order.Items[0].ID, "", "30004")
 	}
 
-	if jobs[0].Vacancies == 0 {
+	if jobs[0].Status == dbJob.JobStatusClosed {
+		return nil, getError("Job is closed: "+jobs[0].ID, "", "40002")
+	}
+
+	if jobs[0].AvailableSlots() == 0 && !jobs[0].Waitlist {
 		return nil, getError("No vacancies available for job: "+jobs[0].ID, "", "40002")
 	}
 
@@ -183,13 +233,13 @@ func (j *Onest) SendJobFulfillment(payload *selectrequest.SelectRequest) {
 	response := onest.BuildSendJobFulfillmentResponse(payload)
 
 	var selectResponseAck selectresponseack.SelectResponseAck
-	if err := j.clients.ApiClient.ApiCall(response, payload.Context.BapURI+"/on_select", &selectResponseAck, "POST"); err != nil {
-		logrus.Errorf("Failed to send job fulfillment response, %v", err)
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_select", &selectResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send job fulfillment response, %v", err)
 		return
 	}
 
 	if selectResponseAck.Error.Message != "" {
-		logrus.Errorf("Received error while sending job fulfillment, %v", selectResponseAck.Error.Message)
+		j.logger().Errorf("Received error while sending job fulfillment, %v", selectResponseAck.Error.Message)
 		return
 	}
 }
@@ -229,6 +279,22 @@ func (j *Onest) InitializeJobApplicationAck(body io.ReadCloser) (*initrequest.In
 		return nil, getError("no items found", ".message.order.items", "")
 	}
 
+	if getDuplicateApplicationPolicy() == duplicatePolicyReject {
+		var (
+			customerContact = payload.Message.Order.Fulfillments[0].Customer.Contact
+			applicantKeys   = dbJobApplication.GetApplicantKeys(customerContact.Phone, customerContact.Email)
+		)
+
+		duplicate, err := j.findDuplicateJobApplication(payload.Message.Order.Items[0].ID, applicantKeys)
+		if err != nil {
+			return nil, getError(err.Error(), "", "")
+		}
+
+		if duplicate != nil {
+			return nil, getError("the applicant has already applied for job: "+payload.Message.Order.Items[0].ID, ".message.order.fulfillments[0].customer.contact", "40003")
+		}
+	}
+
 	return &payload, &initrequestack.InitRequestAck{
 		Message: initrequestack.Message{
 			Ack: initrequestack.Ack{
@@ -242,18 +308,22 @@ func (j *Onest) InitializeJobApplicationAck(body io.ReadCloser) (*initrequest.In
 func (j *Onest) InitializeJobApplication(payload *initrequest.InitRequest) {
 	age, err := strconv.Atoi(payload.Message.Order.Fulfillments[0].Customer.Person.Age)
 	if err != nil {
-		logrus.Errorf("Failed to convert age to int, %v", err)
+		j.logger().Errorf("Failed to convert age to int, %v", err)
 	}
 
 	experience, err := getExeperience(payload)
 	if err != nil {
-		logrus.Errorf("Failed to get experience, %v", err)
+		j.logger().Errorf("Failed to get experience, %v", err)
 	}
 
 	initJobApplication := dbInitJobApplication.InitJobApplication{
 		TransactionID: payload.Context.TransactionID,
 		JobID:         payload.Message.Order.Items[0].ID,
-		CreatedAt:     time.Now(),
+		ApplicantKeys: dbJobApplication.GetApplicantKeys(
+			payload.Message.Order.Fulfillments[0].Customer.Contact.Phone,
+			payload.Message.Order.Fulfillments[0].Customer.Contact.Email,
+		),
+		CreatedAt: time.Now(),
 		ApplicantDetails: dbInitJobApplication.ApplicantDetails{
 			Name:   payload.Message.Order.Fulfillments[0].Customer.Person.Name,
 			Gender: payload.Message.Order.Fulfillments[0].Customer.Person.Gender,
@@ -268,20 +338,32 @@ func (j *Onest) InitializeJobApplication(payload *initrequest.InitRequest) {
 	}
 
 	if err := j.clients.InitJobApplicationClient.CreateInitJobApplication(&initJobApplication); err != nil {
-		logrus.Errorf("Failed to create init job application, %v", err)
+		j.logger().Errorf("Failed to create init job application, %v", err)
 		return
 	}
 
-	response := onest.BuildInitializeJobApplicationResponse(payload)
+	// the draft holds only the personal details of the applicant, which are not recorded
+	audit.NewAudit(j.clients).Record(audit.BAPSource(payload.Context.BapID, payload.Context.MessageID), dbAudit.ActionJobApplicationInitialized,
+		dbAudit.Target{JobID: initJobApplication.JobID}, nil, nil)
+
+	var consentTerms *dbBusiness.ConsentTerms
+	if job, err := j.clients.JobClient.GetJob(initJobApplication.JobID); err != nil {
+		j.logger().Errorf("Failed to get %s job, %v", initJobApplication.JobID, err)
+		consentTerms = j.getConsentTerms("")
+	} else {
+		consentTerms = j.getConsentTerms(job.Business.ID)
+	}
+
+	response := onest.BuildInitializeJobApplicationResponse(payload, consentTerms)
 
 	var initResponseAck initresponseack.InitResponseAck
-	if err := j.clients.ApiClient.ApiCall(response, payload.Context.BapURI+"/on_init", &initResponseAck, "POST"); err != nil {
-		logrus.Errorf("Failed to send init job application response, %v", err)
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_init", &initResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send init job application response, %v", err)
 		return
 	}
 
 	if initResponseAck.Error.Message != "" {
-		logrus.Errorf("Received error while sending job application init response, %v", initResponseAck.Error.Message)
+		j.logger().Errorf("Received error while sending job application init response, %v", initResponseAck.Error.Message)
 		return
 	}
 }
@@ -314,21 +396,57 @@ func (j *Onest) ConfirmJobApplicationAck(body io.ReadCloser) (*confirmrequest.Co
 	}
 
 	if payload.Message.Order.Fulfillments == nil {
-		logrus.Errorf("No fulfillments found")
+		j.logger().Errorf("No fulfillments found")
 		return nil, nil, getError("no fulfillments found", ".message.order.fulfillments", "30004")
 	}
 
 	if payload.Message.Order.Items == nil {
-		logrus.Errorf("No items found")
+		j.logger().Errorf("No items found")
 		return nil, nil, getError("no items found", ".message.order.items", "30004")
 	}
 
+	job, err := j.clients.JobClient.GetJob(payload.Message.Order.Items[0].ID)
+	if err != nil {
+		j.logger().Errorf("No job found for %s id, %v", payload.Message.Order.Items[0].ID, err)
+		return nil, nil, getError("no job found for id: "+payload.Message.Order.Items[0].ID, ".message.order.items[0].id", "30004")
+	}
+
+	if job.Status == dbJob.JobStatusClosed {
+		return nil, nil, getError("job is closed: "+job.ID, ".message.order.items[0].id", "40002")
+	}
+
+	if job.AvailableSlots() == 0 && !job.Waitlist {
+		return nil, nil, getError("no vacancies available for job: "+job.ID, ".message.order.items[0].id", "40002")
+	}
+
 	initJobApplication, err := j.clients.InitJobApplicationClient.GetInitJobApplication(payload.Context.TransactionID)
 	if err != nil {
-		logrus.Errorf("No init job application found for %s transaction-id, %v", payload.Context.TransactionID, err)
+		j.logger().Errorf("No init job application found for %s transaction-id, %v", payload.Context.TransactionID, err)
 		return nil, nil, getError("no init job application found for the given transaction-id", "", "30004")
 	}
 
+	if getDuplicateApplicationPolicy() == duplicatePolicyReject {
+		applicantKeys := dbJobApplication.GetApplicantKeys(initJobApplication.ApplicantDetails.Phone, initJobApplication.ApplicantDetails.Email)
+
+		duplicate, err := j.findDuplicateJobApplication(job.ID, applicantKeys)
+		if err != nil {
+			return nil, nil, getError(err.Error(), "", "")
+		}
+
+		if duplicate != nil {
+			return nil, nil, getError("the applicant has already applied for job: "+job.ID, ".message.order.fulfillments[0].customer.contact", "40003")
+		}
+	}
+
+	consent, err := getConsent(payload.Message.Order.Tags)
+	if err != nil {
+		return nil, nil, getError(err.Error(), ".message.order.tags", "40004")
+	}
+
+	if err := validateConsent(consent, j.getConsentTerms(job.Business.ID)); err != nil {
+		return nil, nil, getError(err.Error(), ".message.order.tags", "40004")
+	}
+
 	return &payload, initJobApplication, &confirmrequestack.ConfirmRequestAck{
 		Message: confirmrequestack.Message{
 			Ack: confirmrequestack.Ack{
@@ -341,55 +459,142 @@ func (j *Onest) ConfirmJobApplicationAck(body io.ReadCloser) (*confirmrequest.Co
 
 func (j *Onest) ConfirmJobApplication(payload *confirmrequest.ConfirmRequest, initJobApplication *dbInitJobApplication.InitJobApplication) {
 	if err := j.clients.InitJobApplicationClient.DeleteInitJobApplication(payload.Context.TransactionID); err != nil {
-		logrus.Errorf("Failed to delete init job application for %s transaction-id, %v", payload.Context.TransactionID, err)
-	}
-
-	if err := j.clients.JobApplicationClient.CreateJobApplication(&dbJobApplication.JobApplication{
-		ID:    payload.Message.Order.ID,
-		JobID: payload.Message.Order.Items[0].ID,
-		ApplicantDetails: dbJobApplication.ApplicantDetails{
-			Name:   initJobApplication.ApplicantDetails.Name,
-			Gender: initJobApplication.ApplicantDetails.Gender,
-			Age:    initJobApplication.ApplicantDetails.Age,
-			Experience: dbJobApplication.Experience{
-				Years: initJobApplication.ApplicantDetails.Experience.Years,
-			},
-			Documents: getJobApplicationDocuments(initJobApplication),
-			Phone:     initJobApplication.ApplicantDetails.Phone,
-			Email:     initJobApplication.ApplicantDetails.Email,
-		},
-		Status:    dbJobApplication.JobApplicationStatusApplicationAccepted,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}); err != nil {
-		logrus.Errorf("Failed to create %s job application, %v", payload.Message.Order.ID, err)
-		return
+		j.logger().Errorf("Failed to delete init job application for %s transaction-id, %v", payload.Context.TransactionID, err)
 	}
 
 	var (
-		query  = bson.D{{Key: "id", Value: payload.Message.Order.Items[0].ID}}
-		update = bson.D{{Key: "$inc", Value: bson.D{{Key: "vacancies", Value: -1}}}}
+		jobID          = payload.Message.Order.Items[0].ID
+		applicantKeys  = dbJobApplication.GetApplicantKeys(initJobApplication.ApplicantDetails.Phone, initJobApplication.ApplicantDetails.Email)
+		jobApplication = &dbJobApplication.JobApplication{
+			ID:    payload.Message.Order.ID,
+			JobID: jobID,
+			ApplicantDetails: dbJobApplication.ApplicantDetails{
+				Name:   initJobApplication.ApplicantDetails.Name,
+				Gender: initJobApplication.ApplicantDetails.Gender,
+				Age:    initJobApplication.ApplicantDetails.Age,
+				Experience: dbJobApplication.Experience{
+					Years: initJobApplication.ApplicantDetails.Experience.Years,
+				},
+				Documents: getJobApplicationDocuments(initJobApplication),
+				Phone:     initJobApplication.ApplicantDetails.Phone,
+				Email:     initJobApplication.ApplicantDetails.Email,
+			},
+			BecknContext: &dbJobApplication.BecknContext{
+				Domain:        payload.Context.Domain,
+				Version:       payload.Context.Version,
+				BapID:         payload.Context.BapID,
+				BapURI:        payload.Context.BapURI,
+				BppID:         payload.Context.BppID,
+				BppURI:        payload.Context.BppURI,
+				TransactionID: payload.Context.TransactionID,
+				City:          payload.Context.Location.City.Code,
+				Country:       payload.Context.Location.Country.Code,
+			},
+			ApplicantKeys: applicantKeys,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
 	)
 
-	if err := j.clients.JobClient.UpdateJob(query, update); err != nil {
-		logrus.Errorf("Failed to update %s job vacancies, %v", payload.Message.Order.Items[0].ID, err)
+	consent, err := getConsent(payload.Message.Order.Tags)
+	if err != nil {
+		j.logger().Errorf("Failed to get the consent of %s job application, %v", payload.Message.Order.ID, err)
 		return
 	}
+	consent.RecordedAt = time.Now()
+	jobApplication.Consent = consent
+
+	// a concurrent confirm of the same applicant may create its application in between the duplicate
+	// check and the creation, the creation then fails on the application keys and the check is repeated
+	var merged bool
+
+	for attempt := 1; !merged; attempt++ {
+		duplicate, err := j.findDuplicateJobApplication(jobID, applicantKeys)
+		if err != nil {
+			j.logger().Errorf("Failed to look up duplicates of %s job application, %v", payload.Message.Order.ID, err)
+		}
+
+		jobApplication.ApplicationKeys = dbJobApplication.GetApplicationKeys(jobID, applicantKeys)
+
+		if duplicate != nil {
+			switch getDuplicateApplicationPolicy() {
+			case duplicatePolicyReject:
+				j.logger().Errorf("Rejected %s job application, the applicant already applied with %s", payload.Message.Order.ID, duplicate.ID)
+				j.sendConfirmError(payload, "40003", ".message.order.fulfillments[0].customer.contact", "the applicant has already applied for job: "+jobID)
+				return
+			case duplicatePolicyMerge:
+				if jobApplication, err = j.mergeJobApplication(duplicate, jobApplication); err != nil {
+					j.logger().Errorf("Failed to merge %s job application into %s, %v", payload.Message.Order.ID, duplicate.ID, err)
+					return
+				}
+				merged = true
+
+				audit.NewAudit(j.clients).Record(audit.BAPSource(payload.Context.BapID, payload.Context.MessageID), dbAudit.ActionJobApplicationMerged,
+					dbAudit.Target{JobID: jobID, JobApplicationID: jobApplication.ID}, duplicate, jobApplication)
+
+				// a merged application keeps the status of the earlier application
+				continue
+			case duplicatePolicyFlag:
+				jobApplication.DuplicateOf = duplicate.ID
+				jobApplication.ApplicationKeys = nil
+			}
+		}
+
+		if jobApplication.Status, err = j.getNewJobApplicationStatus(jobID); err != nil {
+			j.logger().Errorf("Failed to claim an application slot of %s job for %s job application, %v", jobID, payload.Message.Order.ID, err)
+			return
+		}
+
+		err = j.clients.JobApplicationClient.CreateJobApplication(jobApplication)
+		if err == nil {
+			audit.NewAudit(j.clients).Record(audit.BAPSource(payload.Context.BapID, payload.Context.MessageID), dbAudit.ActionJobApplicationCreated,
+				dbAudit.Target{JobID: jobID, JobApplicationID: jobApplication.ID}, nil, jobApplication)
+			break
+		}
+
+		if jobApplication.Status != dbJobApplication.JobApplicationStatusWaitlisted {
+			if err := j.releaseApplicationSlot(jobID); err != nil {
+				j.logger().Errorf("Failed to give back the claimed application slot of %s job, %v", jobID, err)
+			}
+		}
+
+		if !mongo.IsDuplicateKeyError(err) || attempt > 1 {
+			j.logger().Errorf("Failed to create %s job application, %v", payload.Message.Order.ID, err)
+			return
+		}
+	}
+
+	j.linkApplicantProfile(jobApplication)
 
-	response := onest.BuildConfirmJobApplicationResponse(payload)
+	response := onest.BuildConfirmJobApplicationResponse(payload, jobApplication)
 
 	var initResponseAck confirmresponseack.ConfirmResponseAck
-	if err := j.clients.ApiClient.ApiCall(response, payload.Context.BapURI+"/on_confirm", &initResponseAck, "POST"); err != nil {
-		logrus.Errorf("Failed to send jobs, %v", err)
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_confirm", &initResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send jobs, %v", err)
 		return
 	}
 
 	if initResponseAck.Error.Message != "" {
-		logrus.Errorf("Received error while sending job application creation response, %v", initResponseAck.Error.Message)
+		j.logger().Errorf("Received error while sending job application creation response, %v", initResponseAck.Error.Message)
 		return
 	}
 }
 
+// sendConfirmError sends the on_confirm error of a job application rejected after its confirm was acked
+func (j *Onest) sendConfirmError(payload *confirmrequest.ConfirmRequest, code, paths, message string) {
+	response := onest.BuildConfirmJobApplicationErrorResponse(payload, code, paths, message)
+
+	var confirmResponseAck confirmresponseack.ConfirmResponseAck
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_confirm", &confirmResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send job application rejection response, %v", err)
+		return
+	}
+
+	if confirmResponseAck.Error.Message != "" {
+		j.logger().Errorf("Received error while sending job application rejection response, %v", confirmResponseAck.Error.Message)
+	}
+}
+
 func (j *Onest) JobApplicationStatusAck(body io.ReadCloser) (*statusrequest.StatusRequest, *statusrequestack.StatusRequestAck) {
 	var (
 		payload  statusrequest.StatusRequest
@@ -430,20 +635,20 @@ func (j *Onest) JobApplicationStatusAck(body io.ReadCloser) (*statusrequest.Stat
 func (j *Onest) JobApplicationStatus(payload *statusrequest.StatusRequest) {
 	jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(payload.Message.Order.ID)
 	if err != nil {
-		logrus.Errorf("Failed to get %s job application, %v", payload.Message.Order.ID, err)
+		j.logger().Errorf("Failed to get %s job application, %v", payload.Message.Order.ID, err)
 		return
 	}
 
 	response := onest.BuildJobApplicationStatusResponse(payload, jobApplication)
 
 	var statusResponseAck statusresponseack.StatusResponseAck
-	if err := j.clients.ApiClient.ApiCall(response, payload.Context.BapURI+"/on_status", &statusResponseAck, "POST"); err != nil {
-		logrus.Errorf("Failed to send job application status response, %v", err)
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_status", &statusResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send job application status response, %v", err)
 		return
 	}
 
 	if statusResponseAck.Error.Message != "" {
-		logrus.Errorf("Received error while sending job application status response, %v", statusResponseAck.Error.Message)
+		j.logger().Errorf("Received error while sending job application status response, %v", statusResponseAck.Error.Message)
 		return
 	}
 }
@@ -475,6 +680,28 @@ func (j *Onest) WithdrawJobApplicationAck(body io.ReadCloser) (*cancelrequest.Ca
 		return nil, getError(err.Error(), "", "")
 	}
 
+	if payload.Message.OrderID == "" {
+		return nil, getError("no order id found", ".message.order_id", "30004")
+	}
+
+	jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(payload.Message.OrderID)
+	if err != nil {
+		j.logger().Errorf("No job application found for %s order-id, %v", payload.Message.OrderID, err)
+		return nil, getError("no job application found for the given order-id", ".message.order_id", "30004")
+	}
+
+	if !slices.Contains(dbJobApplication.CancellableStatuses, jobApplication.Status) {
+		return nil, getError("cancellation is not allowed for a job application in "+string(jobApplication.Status)+" status", ".message.order_id", "50001")
+	}
+
+	if reasonID := payload.Message.CancellationReasonID; reasonID == "" {
+		if config.Config.CancellationReasonRequired {
+			return nil, getError("cancellation reason is required", ".message.cancellation_reason_id", "50002")
+		}
+	} else if _, ok := config.Config.CancellationReasons[reasonID]; !ok {
+		return nil, getError("invalid cancellation reason id: "+reasonID, ".message.cancellation_reason_id", "50002")
+	}
+
 	return &payload, &cancelrequestack.CancelRequestAck{
 		Message: cancelrequestack.Message{
 			Ack: cancelrequestack.Ack{
@@ -486,208 +713,1207 @@ func (j *Onest) WithdrawJobApplicationAck(body io.ReadCloser) (*cancelrequest.Ca
 }
 
 func (j *Onest) WithdrawJobApplication(payload *cancelrequest.CancelRequest) {
+	previous, err := j.clients.JobApplicationClient.GetJobApplication(payload.Message.OrderID)
+	if err != nil {
+		j.logger().Errorf("Failed to get %s job application, %v", payload.Message.OrderID, err)
+		return
+	}
+
 	var (
-		query  = bson.D{{Key: "id", Value: payload.Message.OrderID}}
+		now   = time.Now()
+		query = bson.D{
+			{Key: "id", Value: payload.Message.OrderID},
+			{Key: "status", Value: previous.Status},
+		}
 		update = bson.D{{Key: "$set", Value: bson.D{
 			{Key: "status", Value: dbJobApplication.JobApplicationStatusCancelled},
+			{Key: "cancellation", Value: dbJobApplication.Cancellation{
+				ReasonID:    payload.Message.CancellationReasonID,
+				Reason:      config.Config.CancellationReasons[payload.Message.CancellationReasonID],
+				CancelledBy: dbJobApplication.CancelledByApplicant,
+				CancelledAt: now,
+			}},
+			{Key: "updated_at", Value: now},
+		}}, {Key: "$unset", Value: bson.D{
+			// the applicant can apply to the job again
+			{Key: "application_keys", Value: ""},
 		}}}
 	)
 
 	jobApplication, err := j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, update)
 	if err != nil {
-		logrus.Errorf("Failed to update %s job application as withdrawn, %v", payload.Message.OrderID, err)
+		j.logger().Errorf("Failed to update %s job application as withdrawn, %v", payload.Message.OrderID, err)
 		return
 	}
 
-	query = bson.D{{Key: "id", Value: jobApplication.JobID}}
-	update = bson.D{{Key: "$inc", Value: bson.D{{Key: "vacancies", Value: 1}}}}
+	audit.NewAudit(j.clients).Record(audit.BAPSource(payload.Context.BapID, payload.Context.MessageID), dbAudit.ActionJobApplicationCancelled,
+		dbAudit.Target{JobID: jobApplication.JobID, JobApplicationID: jobApplication.ID}, previous, jobApplication)
 
-	if err := j.clients.JobClient.UpdateJob(query, update); err != nil {
-		logrus.Errorf("Failed to update %s job vacancies, %v", jobApplication.JobID, err)
-		return
+	if err := j.UpdateJobCounters(jobApplication.JobID, previous.Status, jobApplication.Status); err != nil {
+		j.logger().Errorf("Failed to update %s job counters, %v", jobApplication.JobID, err)
 	}
 
 	response := onest.BuildWithdrawJobApplicationResponse(payload, jobApplication)
 
 	var cancelResponseAck cancelresponseack.CancelResponseAck
-	if err := j.clients.ApiClient.ApiCall(response, payload.Context.BapURI+"/on_cancel", &cancelResponseAck, "POST"); err != nil {
-		logrus.Errorf("Failed to send job application withdrawal response, %v", err)
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_cancel", &cancelResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send job application withdrawal response, %v", err)
 		return
 	}
 
 	if cancelResponseAck.Error.Message != "" {
-		logrus.Errorf("Received error while sending job application withdrawal response, %v", cancelResponseAck.Error.Message)
+		j.logger().Errorf("Received error while sending job application withdrawal response, %v", cancelResponseAck.Error.Message)
 		return
 	}
 }
 
-func getExeperience(payload *initrequest.InitRequest) (int, error) {
-	for _, tag := range payload.Message.Order.Fulfillments[0].Customer.Person.Tags {
-		if tag.Descriptor.Code == "WORK_EXPERIENCE" {
-			if len(tag.List) == 0 {
-				return 0, nil
+func (j *Onest) UpdateJobApplicationAck(body io.ReadCloser) (*updaterequest.UpdateRequest, *dbJobApplication.JobApplication, *updaterequestack.UpdateRequestAck) {
+	var (
+		payload  updaterequest.UpdateRequest
+		getError = func(message, paths, code string) *updaterequestack.UpdateRequestAck {
+			if code == "" {
+				code = "10000"
+			}
+
+			return &updaterequestack.UpdateRequestAck{
+				Message: updaterequestack.Message{
+					Ack: updaterequestack.Ack{
+						Status: "NACK",
+					},
+				},
+				Error: &updaterequestack.Error{
+					Code:    code,
+					Paths:   paths,
+					Message: message,
+				},
 			}
-			return extractYears(tag.List[0].Value)
 		}
+	)
+
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return nil, nil, getError(err.Error(), "", "")
 	}
 
-	return 0, nil
-}
+	if payload.Message.Order.ID == "" {
+		return nil, nil, getError("no order id found", ".message.order.id", "30004")
+	}
 
-func extractYears(duration string) (int, error) {
-	re := regexp.MustCompile(`P(\d+)Y`)
-	matches := re.FindStringSubmatch(duration)
-	if len(matches) < 2 {
-		return 0, fmt.Errorf("invalid duration format")
+	targets := getUpdateTargets(payload.Message.UpdateTarget)
+	if len(targets) == 0 {
+		return nil, nil, getError("no update target found", ".message.update_target", "")
 	}
-	years, err := strconv.Atoi(matches[1])
-	if err != nil {
-		return 0, err
+
+	for _, target := range targets {
+		if _, ok := updatableFields[target]; !ok && target != updateTargetFulfillmentState && target != updateTargetConsent {
+			return nil, nil, getError("update target is not allowed: "+target, ".message.update_target", "")
+		}
 	}
-	return years, nil
-}
 
-func getCredentials(payload *initrequest.InitRequest) dbInitJobApplication.Documents {
-	var documents dbInitJobApplication.Documents
+	// the consent can be revoked without any fulfillment, and in any status of the job application
+	consentOnly := len(targets) == 1 && targets[0] == updateTargetConsent
 
-	for _, cred := range payload.Message.Order.Fulfillments[0].Customer.Person.Creds {
-		switch cred.Descriptor.Name {
-		case "PAN_CARD":
-			documents.PANCard = &dbInitJobApplication.Document{
-				URL:  cred.URL,
-				Type: cred.Type,
-			}
-		case "AADHAAR_CARD":
-			documents.AadharCard = &dbInitJobApplication.Document{
-				URL:  cred.URL,
-				Type: cred.Type,
-			}
-		case "PASSPORT":
-			documents.Passport = &dbInitJobApplication.Document{
-				URL:  cred.URL,
-				Type: cred.Type,
-			}
-		case "DRIVING_LICENSE":
-			documents.DrivingLicense = &dbInitJobApplication.Document{
-				URL:  cred.URL,
-				Type: cred.Type,
-			}
-		case "RESUME":
-			documents.Resume = &dbInitJobApplication.Document{
-				URL:  cred.URL,
-				Type: cred.Type,
-			}
-		}
+	if payload.Message.Order.Fulfillments == nil && !consentOnly {
+		return nil, nil, getError("no fulfillments found", ".message.order.fulfillments", "")
 	}
 
-	return documents
-}
+	fields, err := getJobApplicationUpdate(&payload, targets)
+	if err != nil {
+		return nil, nil, getError(err.Error(), ".message.order.fulfillments[0]", "")
+	}
 
-func getJobApplicationDocuments(initJobApplication *dbInitJobApplication.InitJobApplication) dbJobApplication.Documents {
-	var documents dbJobApplication.Documents
+	jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(payload.Message.Order.ID)
+	if err != nil {
+		j.logger().Errorf("No job application found for %s order-id, %v", payload.Message.Order.ID, err)
+		return nil, nil, getError("no job application found for the given order-id", ".message.order.id", "30004")
+	}
 
-	if initJobApplication.ApplicantDetails.Documents.PANCard != nil {
-		documents.PANCard = &dbJobApplication.Document{
-			URL:  initJobApplication.ApplicantDetails.Documents.PANCard.URL,
-			Type: initJobApplication.ApplicantDetails.Documents.PANCard.Type,
+	// the updated contact details can't be those of another application to the job
+	if applicationKeys, ok := getApplicationKeysUpdate(jobApplication, fields); ok && len(applicationKeys) > 0 {
+		duplicates, err := j.clients.JobApplicationClient.ListJobApplication(bson.D{
+			{Key: "id", Value: bson.D{{Key: "$ne", Value: jobApplication.ID}}},
+			{Key: "application_keys", Value: bson.D{{Key: "$in", Value: applicationKeys}}},
+		})
+		if err != nil {
+			return nil, nil, getError(err.Error(), "", "")
 		}
-	}
 
-	if initJobApplication.ApplicantDetails.Documents.AadharCard != nil {
-		documents.AadharCard = &dbJobApplication.Document{
-			URL:  initJobApplication.ApplicantDetails.Documents.AadharCard.URL,
-			Type: initJobApplication.ApplicantDetails.Documents.AadharCard.Type,
+		if len(duplicates) > 0 {
+			return nil, nil, getError("the applicant has already applied for job: "+jobApplication.JobID, ".message.order.fulfillments[0].customer.contact", "40003")
 		}
 	}
 
-	if initJobApplication.ApplicantDetails.Documents.Passport != nil {
-		documents.Passport = &dbJobApplication.Document{
-			URL:  initJobApplication.ApplicantDetails.Documents.Passport.URL,
-			Type: initJobApplication.ApplicantDetails.Documents.Passport.Type,
+	if slices.Contains(targets, updateTargetConsent) {
+		if !isConsentRevocation(payload.Message.Order.Tags) {
+			return nil, nil, getError("no consent revocation found", ".message.order.tags", "")
 		}
-	}
 
-	if initJobApplication.ApplicantDetails.Documents.DrivingLicense != nil {
-		documents.DrivingLicense = &dbJobApplication.Document{
-			URL:  initJobApplication.ApplicantDetails.Documents.DrivingLicense.URL,
-			Type: initJobApplication.ApplicantDetails.Documents.DrivingLicense.Type,
+		if jobApplication.Consent == nil || jobApplication.Consent.RevokedAt != nil {
+			return nil, nil, getError("no consent to revoke for the given order-id", ".message.order.id", "")
 		}
 	}
 
-	if initJobApplication.ApplicantDetails.Documents.Resume != nil {
-		documents.Resume = &dbJobApplication.Document{
-			URL:  initJobApplication.ApplicantDetails.Documents.Resume.URL,
-			Type: initJobApplication.ApplicantDetails.Documents.Resume.Type,
+	if isTerminalJobApplicationStatus(jobApplication.Status) && !consentOnly {
+		return nil, nil, getError("job application can't be updated in "+string(jobApplication.Status)+" status", ".message.order.id", "")
+	}
+
+	if slices.Contains(targets, updateTargetFulfillmentState) {
+		switch dbJobApplication.JobApplicationStatus(payload.Message.Order.Fulfillments[0].State.Descriptor.Code) {
+		case dbJobApplication.JobApplicationStatusOfferAccepted, dbJobApplication.JobApplicationStatusOfferRejected:
+		default:
+			return nil, nil, getError("unsupported fulfillment state: "+payload.Message.Order.Fulfillments[0].State.Descriptor.Code, ".message.order.fulfillments[0].state.descriptor.code", "")
+		}
+
+		if jobApplication.Status != dbJobApplication.JobApplicationStatusOfferExtended || jobApplication.Offer == nil {
+			return nil, nil, getError("no open offer found for the given order-id", ".message.order.id", "")
+		}
+
+		if time.Now().After(jobApplication.Offer.ExpiresAt) {
+			return nil, nil, getError("the offer has expired", ".message.order.id", "")
 		}
 	}
 
-	return documents
+	return &payload, jobApplication, &updaterequestack.UpdateRequestAck{
+		Message: updaterequestack.Message{
+			Ack: updaterequestack.Ack{
+				Status: "ACK",
+			},
+		},
+		Error: nil,
+	}
 }
 
-func getSearchFilter(payload *searchrequest.SearchRequest) bson.D {
+func (j *Onest) UpdateJobApplication(payload *updaterequest.UpdateRequest, jobApplication *dbJobApplication.JobApplication) {
 	var (
-		role      = payload.Message.Intent.Item.Descriptor.Name
-		provider  = payload.Message.Intent.Provider.Descriptor.Name
-		locations = payload.Message.Intent.Provider.Locations
-		tags      = payload.Message.Intent.Item.Tags
-		query     = bson.D{}
+		now     = time.Now()
+		targets = getUpdateTargets(payload.Message.UpdateTarget)
+		query   = bson.D{
+			{Key: "id", Value: jobApplication.ID},
+			{Key: "status", Value: jobApplication.Status},
+		}
 	)
 
-	if role != "" {
-		query = append(query, bson.E{
-			Key: "$or", Value: bson.A{
-				bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: role}, {Key: "$options", Value: "i"}}}},
-				bson.D{{Key: "description", Value: bson.D{{Key: "$regex", Value: role}, {Key: "$options", Value: "i"}}}},
-			},
-		})
+	fields, err := getJobApplicationUpdate(payload, targets)
+	if err != nil {
+		j.logger().Errorf("Failed to get %s job application update, %v", payload.Message.Order.ID, err)
+		return
 	}
 
-	if provider != "" {
-		query = append(query, bson.E{Key: "business.name", Value: provider})
+	if slices.Contains(targets, updateTargetFulfillmentState) {
+		var (
+			status      = dbJobApplication.JobApplicationStatus(payload.Message.Order.Fulfillments[0].State.Descriptor.Code)
+			offerStatus = dbJobApplication.OfferStatusAccepted
+		)
+
+		if status == dbJobApplication.JobApplicationStatusOfferRejected {
+			offerStatus = dbJobApplication.OfferStatusRejected
+		}
+
+		fields = append(fields,
+			bson.E{Key: "status", Value: status},
+			bson.E{Key: "offer.status", Value: offerStatus},
+			bson.E{Key: "offer.responded_at", Value: now},
+			bson.E{Key: "offer.updated_at", Value: now},
+		)
 	}
 
-	if locations != nil {
-		var location = locations[0]
+	if slices.Contains(targets, updateTargetConsent) {
+		fields = append(fields, bson.E{Key: "consent.revoked_at", Value: now})
+	}
 
-		if location.City.Code != "" {
-			query = append(query, bson.E{Key: "location.city", Value: location.City.Code})
-		}
-		if location.State.Code != "" {
-			query = append(query, bson.E{Key: "location.state", Value: location.State.Code})
-		}
-		if location.AreaCode.Code != "" {
-			query = append(query, bson.E{Key: "location.area_code", Value: location.AreaCode.Code})
-			query = append(query, bson.E{Key: "$or", Value: bson.A{bson.D{{
-				Key: "location.address",
-				Value: bson.D{
-					{Key: "$regex", Value: location.AreaCode.Code},
-					{Key: "$options", Value: "i"},
-				},
-			}}}})
-		}
-		if location.Coordinates.Longitute != 0 && location.Coordinates.Latitude != 0 {
-			query = append(query, bson.E{
-				Key: "address.coordinates", Value: bson.D{
-					{Key: "$nearSphere", Value: bson.D{
-						{Key: "$geometry", Value: bson.D{
-							{Key: "type", Value: "Point"},
-							{
-								Key: "coordinates",
-								Value: bson.A{
-									location.Coordinates.Longitute,
-									location.Coordinates.Latitude,
-								},
-							},
-						}},
-						{Key: "$maxDistance", Value: 5000}, // 5 km in meters
-					}},
-				},
-			})
-		}
+	// the application keys guarding against the duplicate applications follow the contact details
+	contactUpdated := getApplicantKeysUpdate(jobApplication, fields) != jobApplication.ApplicantKeys
+	if applicationKeys, ok := getApplicationKeysUpdate(jobApplication, fields); ok {
+		fields = append(fields, bson.E{Key: "application_keys", Value: applicationKeys})
 	}
 
-	for _, tag := range tags {
-		if tag.Descriptor.Code == "JOB_DETAILS" {
+	fields = append(fields, bson.E{Key: "updated_at", Value: now})
+
+	previous := jobApplication
+
+	jobApplication, err = j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, bson.D{{Key: "$set", Value: fields}})
+	if err != nil {
+		j.logger().Errorf("Failed to update %s job application, %v", payload.Message.Order.ID, err)
+		return
+	}
+
+	audit.NewAudit(j.clients).Record(audit.BAPSource(payload.Context.BapID, payload.Context.MessageID), dbAudit.ActionJobApplicationUpdated,
+		dbAudit.Target{JobID: jobApplication.JobID, JobApplicationID: jobApplication.ID}, previous, jobApplication)
+
+	// the profile is keyed by the contact details of the applicant
+	if contactUpdated {
+		j.linkApplicantProfile(jobApplication)
+	}
+
+	if err := j.UpdateJobCounters(jobApplication.JobID, previous.Status, jobApplication.Status); err != nil {
+		j.logger().Errorf("Failed to update %s job counters, %v", jobApplication.JobID, err)
+	}
+
+	response := onest.BuildUpdateJobApplicationResponse(payload, jobApplication)
+
+	var updateResponseAck updateresponseack.UpdateResponseAck
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_update", &updateResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send job application update response, %v", err)
+		return
+	}
+
+	if updateResponseAck.Error.Message != "" {
+		j.logger().Errorf("Received error while sending job application update response, %v", updateResponseAck.Error.Message)
+		return
+	}
+}
+
+func (j *Onest) SubmitRatingAck(body io.ReadCloser) (*ratingrequest.RatingRequest, *ratingrequestack.RatingRequestAck) {
+	var (
+		payload  ratingrequest.RatingRequest
+		getError = func(message, paths, code string) *ratingrequestack.RatingRequestAck {
+			if code == "" {
+				code = "10000"
+			}
+
+			return &ratingrequestack.RatingRequestAck{
+				Message: ratingrequestack.Message{
+					Ack: ratingrequestack.Ack{
+						Status: "NACK",
+					},
+				},
+				Error: &ratingrequestack.Error{
+					Code:    code,
+					Paths:   paths,
+					Message: message,
+				},
+			}
+		}
+	)
+
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return nil, getError(err.Error(), "", "")
+	}
+
+	if len(payload.Message.Ratings) == 0 {
+		return nil, getError("no ratings found", ".message.ratings", "")
+	}
+
+	// a rating repeated in the request is submitted once, a job application rated twice in the same
+	// category with different values is ambiguous though
+	var (
+		ratings []ratingrequest.Ratings
+		values  = make(map[string]string)
+	)
+
+	for i, rating := range payload.Message.Ratings {
+		path := fmt.Sprintf(".message.ratings[%d]", i)
+
+		key := rating.ID + "/" + strings.ToUpper(rating.RatingCategory)
+		if value, ok := values[key]; ok {
+			if value != rating.Value {
+				return nil, getError("job application is rated more than once in the same category", path, "")
+			}
+			continue
+		}
+		values[key] = rating.Value
+		ratings = append(ratings, rating)
+
+		if _, err := getRatingCategory(rating.RatingCategory); err != nil {
+			return nil, getError(err.Error(), path+".rating_category", "")
+		}
+
+		if _, err := getRatingValue(rating.Value); err != nil {
+			return nil, getError(err.Error(), path+".value", "")
+		}
+
+		jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(rating.ID)
+		if err != nil {
+			j.logger().Errorf("No job application found for %s order-id, %v", rating.ID, err)
+			return nil, getError("no job application found for the given order-id", path+".id", "30004")
+		}
+
+		if !isRateableJobApplicationStatus(jobApplication.Status) {
+			return nil, getError("job application can't be rated in "+string(jobApplication.Status)+" status", path+".id", "")
+		}
+
+		existing, err := j.clients.RatingClient.ListRatings(bson.D{
+			{Key: "job_application_id", Value: rating.ID},
+			{Key: "category", Value: strings.ToUpper(rating.RatingCategory)},
+		})
+		if err != nil {
+			return nil, getError(err.Error(), "", "")
+		}
+
+		if len(existing) > 0 {
+			return nil, getError("job application has already been rated", path, "")
+		}
+	}
+
+	payload.Message.Ratings = ratings
+
+	return &payload, &ratingrequestack.RatingRequestAck{
+		Message: ratingrequestack.Message{
+			Ack: ratingrequestack.Ack{
+				Status: "ACK",
+			},
+		},
+		Error: nil,
+	}
+}
+
+func (j *Onest) SubmitRating(payload *ratingrequest.RatingRequest) {
+	var (
+		ratings = make([]*dbRating.Rating, len(payload.Message.Ratings))
+		errs    = make([]error, len(payload.Message.Ratings))
+		invalid bool
+	)
+
+	// every rating is validated before any is submitted, so that an invalid rating doesn't leave the
+	// request partially submitted
+	for i, rating := range payload.Message.Ratings {
+		if ratings[i], errs[i] = j.getRating(payload, rating); errs[i] != nil {
+			invalid = true
+		}
+	}
+
+	for i, rating := range ratings {
+		if invalid {
+			if errs[i] == nil {
+				errs[i] = errors.New("not submitted as other ratings of the request are invalid")
+			}
+			continue
+		}
+
+		if err := j.clients.RatingClient.CreateRating(rating); err != nil {
+			j.logger().Errorf("Failed to create %s rating for %s job application, %v", rating.Category, rating.JobApplicationID, err)
+
+			errs[i] = errors.New("failed to submit the rating")
+			if mongo.IsDuplicateKeyError(err) {
+				errs[i] = errors.New("job application has already been rated")
+			}
+			continue
+		}
+
+		audit.NewAudit(j.clients).Record(audit.BAPSource(payload.Context.BapID, payload.Context.MessageID), dbAudit.ActionRatingSubmitted,
+			dbAudit.Target{BusinessID: rating.BusinessID, JobID: rating.JobID, JobApplicationID: rating.JobApplicationID, RatingID: rating.ID}, nil, rating)
+	}
+
+	response := onest.BuildSubmitRatingResponse(payload, errs)
+
+	var ratingResponseAck ratingresponseack.RatingResponseAck
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_rating", &ratingResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send rating response, %v", err)
+		return
+	}
+
+	if ratingResponseAck.Error.Message != "" {
+		j.logger().Errorf("Received error while sending rating response, %v", ratingResponseAck.Error.Message)
+		return
+	}
+}
+
+// getRating returns the rating to be submitted, validated again as the job application may have changed
+// since the rating request was acked
+func (j *Onest) getRating(payload *ratingrequest.RatingRequest, rating ratingrequest.Ratings) (*dbRating.Rating, error) {
+	category, err := getRatingCategory(rating.RatingCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := getRatingValue(rating.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(rating.ID)
+	if err != nil {
+		j.logger().Errorf("Failed to get %s job application, %v", rating.ID, err)
+		return nil, errors.New("no job application found for the given order-id")
+	}
+
+	if !isRateableJobApplicationStatus(jobApplication.Status) {
+		return nil, errors.New("job application can't be rated in " + string(jobApplication.Status) + " status")
+	}
+
+	job, err := j.clients.JobClient.GetJob(jobApplication.JobID)
+	if err != nil {
+		j.logger().Errorf("Failed to get %s job, %v", jobApplication.JobID, err)
+		return nil, errors.New("no job found for the job application")
+	}
+
+	return &dbRating.Rating{
+		ID:               random.GetRandomString(10),
+		JobApplicationID: jobApplication.ID,
+		JobID:            job.ID,
+		BusinessID:       job.Business.ID,
+		Category:         category,
+		Value:            value,
+		BapID:            payload.Context.BapID,
+		CreatedAt:        time.Now(),
+	}, nil
+}
+
+func (j *Onest) SendSupportAck(body io.ReadCloser) (*supportrequest.SupportRequest, *supportrequestack.SupportRequestAck) {
+	var (
+		payload  supportrequest.SupportRequest
+		getError = func(message, paths, code string) *supportrequestack.SupportRequestAck {
+			if code == "" {
+				code = "10000"
+			}
+
+			return &supportrequestack.SupportRequestAck{
+				Message: supportrequestack.Message{
+					Ack: supportrequestack.Ack{
+						Status: "NACK",
+					},
+				},
+				Error: &supportrequestack.Error{
+					Code:    code,
+					Paths:   paths,
+					Message: message,
+				},
+			}
+		}
+	)
+
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return nil, getError(err.Error(), "", "")
+	}
+
+	if orderID := payload.Message.Support.OrderID; orderID != "" {
+		if _, err := j.clients.JobApplicationClient.GetJobApplication(orderID); err != nil {
+			j.logger().Errorf("No job application found for %s order-id, %v", orderID, err)
+			return nil, getError("no job application found for the given order-id", ".message.support.order_id", "30004")
+		}
+	}
+
+	return &payload, &supportrequestack.SupportRequestAck{
+		Message: supportrequestack.Message{
+			Ack: supportrequestack.Ack{
+				Status: "ACK",
+			},
+		},
+		Error: nil,
+	}
+}
+
+func (j *Onest) SendSupport(payload *supportrequest.SupportRequest) {
+	response := onest.BuildSendSupportResponse(payload, j.getSupport(payload))
+
+	var supportResponseAck supportresponseack.SupportResponseAck
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, payload.Context.BapURI+"/on_support", &supportResponseAck, "POST"); err != nil {
+		j.logger().Errorf("Failed to send support response, %v", err)
+		return
+	}
+
+	if supportResponseAck.Error.Message != "" {
+		j.logger().Errorf("Received error while sending support response, %v", supportResponseAck.Error.Message)
+		return
+	}
+}
+
+// getSupport resolves the support contacts of the business referred by the support request, either
+// through the order-id or a business id as the ref-id, the contacts missing for the business fall
+// back to the adapter wide support contacts
+func (j *Onest) getSupport(payload *supportrequest.SupportRequest) *dbBusiness.Support {
+	var support dbBusiness.Support
+
+	if orderID := payload.Message.Support.OrderID; orderID != "" {
+		if jobApplication, err := j.clients.JobApplicationClient.GetJobApplication(orderID); err != nil {
+			j.logger().Errorf("Failed to get %s job application, %v", orderID, err)
+		} else if job, err := j.clients.JobClient.GetJob(jobApplication.JobID); err != nil {
+			j.logger().Errorf("Failed to get %s job, %v", jobApplication.JobID, err)
+		} else if business, err := j.clients.BusinessClient.GetBusiness(job.Business.ID); err != nil {
+			j.logger().Errorf("Failed to get %s business, %v", job.Business.ID, err)
+		} else {
+			support = business.Support
+		}
+	} else if refID := payload.Message.Support.RefID; refID != "" {
+		if business, err := j.clients.BusinessClient.GetBusiness(refID); err == nil {
+			support = business.Support
+		}
+	}
+
+	if support.Phone == "" {
+		support.Phone = config.Config.SupportPhone
+	}
+	if support.Email == "" {
+		support.Email = config.Config.SupportEmail
+	}
+	if support.URL == "" {
+		support.URL = config.Config.SupportURL
+	}
+
+	return &support
+}
+
+// getConsentTerms resolves the consent terms of a business, the terms missing for the business
+// fall back to the adapter wide consent terms
+func (j *Onest) getConsentTerms(businessID string) *dbBusiness.ConsentTerms {
+	var consentTerms dbBusiness.ConsentTerms
+
+	if businessID != "" {
+		if business, err := j.clients.BusinessClient.GetBusiness(businessID); err != nil {
+			j.logger().Errorf("Failed to get %s business, %v", businessID, err)
+		} else {
+			consentTerms = business.ConsentTerms
+		}
+	}
+
+	if consentTerms.Version == "" {
+		consentTerms.Version = config.Config.ConsentTermsVersion
+	}
+	if consentTerms.URL == "" {
+		consentTerms.URL = config.Config.ConsentTermsURL
+	}
+	if len(consentTerms.Scopes) == 0 {
+		consentTerms.Scopes = config.Config.ConsentScopes
+	}
+
+	return &consentTerms
+}
+
+// NotifyJobApplicationCancellation sends an unsolicited on_cancel to the BAP of a job application
+// cancelled by the employer
+func (j *Onest) NotifyJobApplicationCancellation(jobApplication *dbJobApplication.JobApplication) error {
+	if jobApplication.BecknContext == nil {
+		return fmt.Errorf("no beckn context found for %s job application", jobApplication.ID)
+	}
+
+	response := onest.BuildJobApplicationCancellationNotification(jobApplication)
+
+	var cancelResponseAck cancelresponseack.CancelResponseAck
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, jobApplication.BecknContext.BapURI+"/on_cancel", &cancelResponseAck, "POST"); err != nil {
+		return fmt.Errorf("failed to send job application cancellation, %v", err)
+	}
+
+	if cancelResponseAck.Error.Message != "" {
+		return fmt.Errorf("received error while sending job application cancellation, %v", cancelResponseAck.Error.Message)
+	}
+
+	return nil
+}
+
+// NotifyJobApplicationStatus sends an unsolicited on_status to the BAP of a job application
+// updated by the BPP
+func (j *Onest) NotifyJobApplicationStatus(jobApplication *dbJobApplication.JobApplication) error {
+	if jobApplication.BecknContext == nil {
+		return fmt.Errorf("no beckn context found for %s job application", jobApplication.ID)
+	}
+
+	response := onest.BuildJobApplicationStatusNotification(jobApplication)
+
+	var statusResponseAck statusresponseack.StatusResponseAck
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, jobApplication.BecknContext.BapURI+"/on_status", &statusResponseAck, "POST"); err != nil {
+		return fmt.Errorf("failed to send job application status, %v", err)
+	}
+
+	if statusResponseAck.Error.Message != "" {
+		return fmt.Errorf("received error while sending job application status, %v", statusResponseAck.Error.Message)
+	}
+
+	return nil
+}
+
+// NotifyJobApplicationUpdate sends an unsolicited on_update to the BAP of a job application
+// updated by the employer, for eg. an offer extended
+func (j *Onest) NotifyJobApplicationUpdate(jobApplication *dbJobApplication.JobApplication) error {
+	if jobApplication.BecknContext == nil {
+		return fmt.Errorf("no beckn context found for %s job application", jobApplication.ID)
+	}
+
+	response := onest.BuildJobApplicationUpdateNotification(jobApplication)
+
+	var updateResponseAck updateresponseack.UpdateResponseAck
+	if err := j.clients.ApiClient.ApiCall(j.clients.Context, response, jobApplication.BecknContext.BapURI+"/on_update", &updateResponseAck, "POST"); err != nil {
+		return fmt.Errorf("failed to send job application update, %v", err)
+	}
+
+	if updateResponseAck.Error.Message != "" {
+		return fmt.Errorf("received error while sending job application update, %v", updateResponseAck.Error.Message)
+	}
+
+	return nil
+}
+
+// findDuplicateJobApplication returns an earlier application to the job by the same applicant, matched
+// on the normalized phone or email, withdrawn applications aren't duplicates as the applicant may apply again
+func (j *Onest) findDuplicateJobApplication(jobID string, applicantKeys dbJobApplication.ApplicantKeys) (*dbJobApplication.JobApplication, error) {
+	var keys bson.A
+
+	if applicantKeys.Phone != "" {
+		keys = append(keys, bson.D{{Key: "applicant_keys.phone", Value: applicantKeys.Phone}})
+	}
+
+	if applicantKeys.Email != "" {
+		keys = append(keys, bson.D{{Key: "applicant_keys.email", Value: applicantKeys.Email}})
+	}
+
+	if keys == nil {
+		return nil, nil
+	}
+
+	jobApplications, err := j.clients.JobApplicationClient.ListJobApplication(bson.D{
+		{Key: "job_id", Value: jobID},
+		{Key: "status", Value: bson.D{{Key: "$ne", Value: dbJobApplication.JobApplicationStatusCancelled}}},
+		{Key: "$or", Value: keys},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jobApplications) == 0 {
+		return nil, nil
+	}
+
+	return &jobApplications[0], nil
+}
+
+// linkApplicantProfile links a confirmed job application to the applicant's profile, creating the profile
+// on the applicant's first application
+func (j *Onest) linkApplicantProfile(jobApplication *dbJobApplication.JobApplication) {
+	key := dbApplicantProfile.GetKey(jobApplication.ApplicantKeys)
+	if key == "" {
+		return
+	}
+
+	profile, err := j.clients.ApplicantProfileClient.UpsertApplicantProfile(key, jobApplication.ApplicantDetails, jobApplication.ID)
+	if err != nil {
+		j.logger().Errorf("Failed to link %s job application to its applicant profile, %v", jobApplication.ID, err)
+		return
+	}
+
+	if jobApplication.ProfileID == profile.ID {
+		return
+	}
+
+	var (
+		query  = bson.D{{Key: "id", Value: jobApplication.ID}}
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: "profile_id", Value: profile.ID}}}}
+	)
+
+	if err := j.clients.JobApplicationClient.UpdateJobApplication(query, update); err != nil {
+		j.logger().Errorf("Failed to link %s job application to %s applicant profile, %v", jobApplication.ID, profile.ID, err)
+		return
+	}
+	jobApplication.ProfileID = profile.ID
+}
+
+// mergeJobApplication updates the earlier application of the applicant with the details of the
+// new application, the BAP is then notified of the earlier application
+func (j *Onest) mergeJobApplication(earlier, jobApplication *dbJobApplication.JobApplication) (*dbJobApplication.JobApplication, error) {
+	var (
+		query = bson.D{{Key: "id", Value: earlier.ID}}
+		set   = bson.D{
+			{Key: "applicant_details", Value: jobApplication.ApplicantDetails},
+			{Key: "applicant_keys", Value: jobApplication.ApplicantKeys},
+			{Key: "beckn_context", Value: jobApplication.BecknContext},
+			{Key: "consent", Value: jobApplication.Consent},
+			{Key: "updated_at", Value: time.Now()},
+		}
+	)
+
+	// the earlier application keeps holding the application keys, of the merged contact details
+	if len(earlier.ApplicationKeys) > 0 {
+		set = append(set, bson.E{Key: "application_keys", Value: jobApplication.ApplicationKeys})
+	}
+
+	merged, err := j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, bson.D{{Key: "$set", Value: set}})
+	if err != nil {
+		return nil, err
+	}
+
+	j.logger().Infof("Merged %s job application into %s", jobApplication.ID, earlier.ID)
+
+	return merged, nil
+}
+
+// UpdateJobCounters keeps the in-progress applications and the hires of the job in step with a job
+// application moving between the statuses, the application slots freed are filled from the waitlist
+func (j *Onest) UpdateJobCounters(jobID string, from, to dbJobApplication.JobApplicationStatus) error {
+	var inc bson.D
+
+	if delta := countActive(to) - countActive(from); delta != 0 {
+		inc = append(inc, bson.E{Key: "applications", Value: delta})
+	}
+
+	if delta := countHired(to) - countHired(from); delta != 0 {
+		inc = append(inc, bson.E{Key: "hired", Value: delta})
+	}
+
+	if inc == nil {
+		return nil
+	}
+
+	if err := j.clients.JobClient.UpdateJob(bson.D{{Key: "id", Value: jobID}}, bson.D{{Key: "$inc", Value: inc}}); err != nil {
+		return err
+	}
+
+	return j.promoteWaitlistedJobApplications(jobID)
+}
+
+// promoteWaitlistedJobApplications moves the oldest waitlisted applications of the job into its
+// available application slots, and notifies their BAPs of the promotion
+func (j *Onest) promoteWaitlistedJobApplications(jobID string) error {
+	for {
+		claimed, err := j.claimApplicationSlot(jobID)
+		if err != nil || !claimed {
+			return err
+		}
+
+		var (
+			query = bson.D{
+				{Key: "job_id", Value: jobID},
+				{Key: "status", Value: dbJobApplication.JobApplicationStatusWaitlisted},
+			}
+			update = bson.D{{Key: "$set", Value: bson.D{
+				{Key: "status", Value: dbJobApplication.JobApplicationStatusApplicationAccepted},
+				{Key: "updated_at", Value: time.Now()},
+			}}}
+			opts = options.FindOneAndUpdate().SetSort(bson.D{{Key: "created_at", Value: 1}})
+		)
+
+		jobApplication, err := j.clients.JobApplicationClient.UpdateJobApplicationAndReturnDocument(query, update, opts)
+		if err != nil {
+			if releaseErr := j.releaseApplicationSlot(jobID); releaseErr != nil {
+				j.logger().Errorf("Failed to give back the claimed application slot of %s job, %v", jobID, releaseErr)
+			}
+
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil
+			}
+			return fmt.Errorf("failed to promote a waitlisted job application, %v", err)
+		}
+
+		j.logger().Infof("Promoted %s job application of %s job from the waitlist", jobApplication.ID, jobID)
+
+		audit.NewAudit(j.clients).Record(audit.SystemSource("waitlist"), dbAudit.ActionJobApplicationPromoted,
+			dbAudit.Target{JobID: jobID, JobApplicationID: jobApplication.ID},
+			bson.M{"status": dbJobApplication.JobApplicationStatusWaitlisted}, bson.M{"status": jobApplication.Status})
+
+		if err := j.NotifyJobApplicationStatus(jobApplication); err != nil {
+			j.logger().Errorf("Failed to notify the promotion of %s job application, %v", jobApplication.ID, err)
+		}
+	}
+}
+
+// getNewJobApplicationStatus claims an application slot of the job for a new job application, the
+// application is waitlisted instead when the job is fully booked and has a waitlist
+func (j *Onest) getNewJobApplicationStatus(jobID string) (dbJobApplication.JobApplicationStatus, error) {
+	claimed, err := j.claimApplicationSlot(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	if claimed {
+		return dbJobApplication.JobApplicationStatusApplicationAccepted, nil
+	}
+
+	job, err := j.clients.JobClient.GetJob(jobID)
+	if err != nil {
+		return "", err
+	}
+
+	if !job.Waitlist {
+		return "", fmt.Errorf("no application slots available for %s job", jobID)
+	}
+
+	return dbJobApplication.JobApplicationStatusWaitlisted, nil
+}
+
+// claimApplicationSlot atomically takes an application slot of the job, it reports whether a slot was available
+func (j *Onest) claimApplicationSlot(jobID string) (bool, error) {
+	var (
+		query  = bson.D{{Key: "id", Value: jobID}, dbJob.AvailableSlotsQuery()}
+		update = bson.D{{Key: "$inc", Value: bson.D{{Key: "applications", Value: 1}}}}
+	)
+
+	if _, err := j.clients.JobClient.UpdateJobAndReturnDocument(query, update); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (j *Onest) releaseApplicationSlot(jobID string) error {
+	var (
+		query  = bson.D{{Key: "id", Value: jobID}}
+		update = bson.D{{Key: "$inc", Value: bson.D{{Key: "applications", Value: -1}}}}
+	)
+
+	return j.clients.JobClient.UpdateJob(query, update)
+}
+
+// countActive returns 1 for the job application statuses holding an application slot
+func countActive(status dbJobApplication.JobApplicationStatus) int {
+	if slices.Contains(dbJobApplication.ActiveStatuses, status) {
+		return 1
+	}
+	return 0
+}
+
+// countHired returns 1 for the job application statuses filling an opening
+func countHired(status dbJobApplication.JobApplicationStatus) int {
+	if status == dbJobApplication.JobApplicationStatusOfferAccepted {
+		return 1
+	}
+	return 0
+}
+
+const (
+	duplicatePolicyReject = "reject"
+	duplicatePolicyMerge  = "merge"
+	duplicatePolicyFlag   = "flag"
+)
+
+// getDuplicateApplicationPolicy returns the configured duplicate application policy, unknown policies reject the duplicates
+func getDuplicateApplicationPolicy() string {
+	switch policy := strings.ToLower(config.Config.DuplicateApplicationPolicy); policy {
+	case duplicatePolicyMerge, duplicatePolicyFlag:
+		return policy
+	default:
+		return duplicatePolicyReject
+	}
+}
+
+func getExeperience(payload *initrequest.InitRequest) (int, error) {
+	for _, tag := range payload.Message.Order.Fulfillments[0].Customer.Person.Tags {
+		if tag.Descriptor.Code == "WORK_EXPERIENCE" {
+			if len(tag.List) == 0 {
+				return 0, nil
+			}
+			return extractYears(tag.List[0].Value)
+		}
+	}
+
+	return 0, nil
+}
+
+// yearsPattern matches the years of an ISO 8601 duration, for eg. 'P2Y'
+var yearsPattern = regexp.MustCompile(`P(\d+)Y`)
+
+func extractYears(duration string) (int, error) {
+	matches := yearsPattern.FindStringSubmatch(duration)
+	if len(matches) < 2 {
+		return 0, fmt.Errorf("invalid duration format")
+	}
+	years, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, err
+	}
+	return years, nil
+}
+
+func getCredentials(payload *initrequest.InitRequest) dbInitJobApplication.Documents {
+	var documents dbInitJobApplication.Documents
+
+	for _, cred := range payload.Message.Order.Fulfillments[0].Customer.Person.Creds {
+		switch cred.Descriptor.Name {
+		case "PAN_CARD":
+			documents.PANCard = &dbInitJobApplication.Document{
+				URL:  cred.URL,
+				Type: cred.Type,
+			}
+		case "AADHAAR_CARD":
+			documents.AadharCard = &dbInitJobApplication.Document{
+				URL:  cred.URL,
+				Type: cred.Type,
+			}
+		case "PASSPORT":
+			documents.Passport = &dbInitJobApplication.Document{
+				URL:  cred.URL,
+				Type: cred.Type,
+			}
+		case "DRIVING_LICENSE":
+			documents.DrivingLicense = &dbInitJobApplication.Document{
+				URL:  cred.URL,
+				Type: cred.Type,
+			}
+		case "RESUME":
+			documents.Resume = &dbInitJobApplication.Document{
+				URL:  cred.URL,
+				Type: cred.Type,
+			}
+		}
+	}
+
+	return documents
+}
+
+func getJobApplicationDocuments(initJobApplication *dbInitJobApplication.InitJobApplication) dbJobApplication.Documents {
+	var documents dbJobApplication.Documents
+
+	if initJobApplication.ApplicantDetails.Documents.PANCard != nil {
+		documents.PANCard = &dbJobApplication.Document{
+			URL:  initJobApplication.ApplicantDetails.Documents.PANCard.URL,
+			Type: initJobApplication.ApplicantDetails.Documents.PANCard.Type,
+		}
+	}
+
+	if initJobApplication.ApplicantDetails.Documents.AadharCard != nil {
+		documents.AadharCard = &dbJobApplication.Document{
+			URL:  initJobApplication.ApplicantDetails.Documents.AadharCard.URL,
+			Type: initJobApplication.ApplicantDetails.Documents.AadharCard.Type,
+		}
+	}
+
+	if initJobApplication.ApplicantDetails.Documents.Passport != nil {
+		documents.Passport = &dbJobApplication.Document{
+			URL:  initJobApplication.ApplicantDetails.Documents.Passport.URL,
+			Type: initJobApplication.ApplicantDetails.Documents.Passport.Type,
+		}
+	}
+
+	if initJobApplication.ApplicantDetails.Documents.DrivingLicense != nil {
+		documents.DrivingLicense = &dbJobApplication.Document{
+			URL:  initJobApplication.ApplicantDetails.Documents.DrivingLicense.URL,
+			Type: initJobApplication.ApplicantDetails.Documents.DrivingLicense.Type,
+		}
+	}
+
+	if initJobApplication.ApplicantDetails.Documents.Resume != nil {
+		documents.Resume = &dbJobApplication.Document{
+			URL:  initJobApplication.ApplicantDetails.Documents.Resume.URL,
+			Type: initJobApplication.ApplicantDetails.Documents.Resume.Type,
+		}
+	}
+
+	return documents
+}
+
+// updateTargetFulfillmentState is the update target used by the applicants to respond to a job offer
+const updateTargetFulfillmentState = "fulfillments.state"
+
+// updateTargetConsent is the update target used by the applicants to revoke their consent
+const updateTargetConsent = "tags"
+
+// updatableFields is the whitelist of update targets an applicant can update
+// after confirm, mapped to the job application fields they update
+var updatableFields = map[string]string{
+	"fulfillments.customer.contact.phone": "applicant_details.phone",
+	"fulfillments.customer.contact.email": "applicant_details.email",
+	"fulfillments.customer.person.creds":  "applicant_details.documents",
+}
+
+// documentFields maps the credential names to the job application document fields
+var documentFields = map[string]string{
+	"PAN_CARD":        "pan_card",
+	"AADHAAR_CARD":    "aadhar_card",
+	"PASSPORT":        "passport",
+	"DRIVING_LICENSE": "driving_license",
+	"RESUME":          "resume",
+}
+
+// indexPattern matches the array indexes of the update targets, for eg. '[0]'
+var indexPattern = regexp.MustCompile(`\[\d*\]`)
+
+// getUpdateTargets splits and normalizes comma separated update targets,
+// for eg. 'order.fulfillments[0].state' to 'fulfillments.state'
+func getUpdateTargets(updateTarget string) []string {
+	var targets []string
+
+	for _, target := range strings.Split(updateTarget, ",") {
+		target = strings.TrimPrefix(strings.TrimSpace(target), "order.")
+		if target == "" {
+			continue
+		}
+		targets = append(targets, indexPattern.ReplaceAllString(target, ""))
+	}
+
+	return targets
+}
+
+// getJobApplicationUpdate returns the job application fields to be set for the whitelisted update targets
+func getJobApplicationUpdate(payload *updaterequest.UpdateRequest, targets []string) (bson.D, error) {
+	var (
+		fields   bson.D
+		customer updaterequest.Customer
+	)
+
+	if len(payload.Message.Order.Fulfillments) > 0 {
+		customer = payload.Message.Order.Fulfillments[0].Customer
+	}
+
+	for _, target := range targets {
+		field, ok := updatableFields[target]
+		if !ok {
+			continue
+		}
+
+		switch target {
+		case "fulfillments.customer.contact.phone":
+			if customer.Contact.Phone == "" {
+				return nil, fmt.Errorf("no phone found")
+			}
+			fields = append(fields,
+				bson.E{Key: field, Value: customer.Contact.Phone},
+				bson.E{Key: "applicant_keys.phone", Value: dbJobApplication.GetApplicantKeys(customer.Contact.Phone, "").Phone},
+			)
+		case "fulfillments.customer.contact.email":
+			if customer.Contact.Email == "" {
+				return nil, fmt.Errorf("no email found")
+			}
+			fields = append(fields,
+				bson.E{Key: field, Value: customer.Contact.Email},
+				bson.E{Key: "applicant_keys.email", Value: dbJobApplication.GetApplicantKeys("", customer.Contact.Email).Email},
+			)
+		case "fulfillments.customer.person.creds":
+			if len(customer.Person.Creds) == 0 {
+				return nil, fmt.Errorf("no creds found")
+			}
+			for _, cred := range customer.Person.Creds {
+				document, ok := documentFields[cred.Descriptor.Name]
+				if !ok {
+					return nil, fmt.Errorf("unsupported cred: %s", cred.Descriptor.Name)
+				}
+				if cred.URL == "" {
+					return nil, fmt.Errorf("no url found for cred: %s", cred.Descriptor.Name)
+				}
+				fields = append(fields, bson.E{Key: field + "." + document, Value: dbJobApplication.Document{
+					URL:  cred.URL,
+					Type: cred.Type,
+				}})
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// getApplicantKeysUpdate returns the applicant keys of a job application with the contact details set by the
+// update fields
+func getApplicantKeysUpdate(jobApplication *dbJobApplication.JobApplication, fields bson.D) dbJobApplication.ApplicantKeys {
+	applicantKeys := jobApplication.ApplicantKeys
+
+	for _, field := range fields {
+		switch field.Key {
+		case "applicant_keys.phone":
+			applicantKeys.Phone, _ = field.Value.(string)
+		case "applicant_keys.email":
+			applicantKeys.Email, _ = field.Value.(string)
+		}
+	}
+
+	return applicantKeys
+}
+
+// getApplicationKeysUpdate returns the application keys of a job application with the contact details set by
+// the update fields, and whether they changed, the applications holding no keys, for eg. the flagged
+// duplicates, keep holding none
+func getApplicationKeysUpdate(jobApplication *dbJobApplication.JobApplication, fields bson.D) ([]string, bool) {
+	applicantKeys := getApplicantKeysUpdate(jobApplication, fields)
+	if len(jobApplication.ApplicationKeys) == 0 || applicantKeys == jobApplication.ApplicantKeys {
+		return nil, false
+	}
+
+	return dbJobApplication.GetApplicationKeys(jobApplication.JobID, applicantKeys), true
+}
+
+// isTerminalJobApplicationStatus reports whether a job application in the given status can no longer change
+func isTerminalJobApplicationStatus(status dbJobApplication.JobApplicationStatus) bool {
+	switch status {
+	case dbJobApplication.JobApplicationStatusApplicationRejected,
+		dbJobApplication.JobApplicationStatusOfferRejected,
+		dbJobApplication.JobApplicationStatusOfferExpired,
+		dbJobApplication.JobApplicationStatusCancelled,
+		dbJobApplication.JobApplicationStatusJobClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRateableJobApplicationStatus reports whether a job application in the given status has reached
+// a terminal state, and hence can be rated by the applicant
+func isRateableJobApplicationStatus(status dbJobApplication.JobApplicationStatus) bool {
+	return status == dbJobApplication.JobApplicationStatusOfferAccepted || isTerminalJobApplicationStatus(status)
+}
+
+func getRatingCategory(category string) (dbRating.Category, error) {
+	switch dbRating.Category(strings.ToUpper(category)) {
+	case dbRating.CategoryProvider:
+		return dbRating.CategoryProvider, nil
+	case dbRating.CategoryItem:
+		return dbRating.CategoryItem, nil
+	default:
+		return "", fmt.Errorf("invalid rating category %s", category)
+	}
+}
+
+func getRatingValue(value string) (int, error) {
+	rating, err := strconv.Atoi(value)
+	if err != nil || rating < dbRating.MinValue || rating > dbRating.MaxValue {
+		return 0, fmt.Errorf("invalid rating value %s, it shall be between %d and %d", value, dbRating.MinValue, dbRating.MaxValue)
+	}
+
+	return rating, nil
+}
+
+func getSearchFilter(payload *searchrequest.SearchRequest) bson.D {
+	var (
+		role      = payload.Message.Intent.Item.Descriptor.Name
+		provider  = payload.Message.Intent.Provider.Descriptor.Name
+		locations = payload.Message.Intent.Provider.Locations
+		tags      = payload.Message.Intent.Item.Tags
+		query     = bson.D{
+			{Key: "status", Value: bson.D{{Key: "$ne", Value: dbJob.JobStatusClosed}}},
+			dbJob.RemainingVacanciesQuery(),
+		}
+	)
+
+	if role != "" {
+		query = append(query, bson.E{
+			Key: "$or", Value: bson.A{
+				bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: role}, {Key: "$options", Value: "i"}}}},
+				bson.D{{Key: "description", Value: bson.D{{Key: "$regex", Value: role}, {Key: "$options", Value: "i"}}}},
+			},
+		})
+	}
+
+	if provider != "" {
+		query = append(query, bson.E{Key: "business.name", Value: provider})
+	}
+
+	if locations != nil {
+		var location = locations[0]
+
+		if location.City.Code != "" {
+			query = append(query, bson.E{Key: "location.city", Value: location.City.Code})
+		}
+		if location.State.Code != "" {
+			query = append(query, bson.E{Key: "location.state", Value: location.State.Code})
+		}
+		if location.AreaCode.Code != "" {
+			query = append(query, bson.E{Key: "location.area_code", Value: location.AreaCode.Code})
+			query = append(query, bson.E{Key: "$or", Value: bson.A{bson.D{{
+				Key: "location.address",
+				Value: bson.D{
+					{Key: "$regex", Value: location.AreaCode.Code},
+					{Key: "$options", Value: "i"},
+				},
+			}}}})
+		}
+		if location.Coordinates.Longitute != 0 && location.Coordinates.Latitude != 0 {
+			query = append(query, bson.E{
+				Key: "address.coordinates", Value: bson.D{
+					{Key: "$nearSphere", Value: bson.D{
+						{Key: "$geometry", Value: bson.D{
+							{Key: "type", Value: "Point"},
+							{
+								Key: "coordinates",
+								Value: bson.A{
+									location.Coordinates.Longitute,
+									location.Coordinates.Latitude,
+								},
+							},
+						}},
+						{Key: "$maxDistance", Value: 5000}, // 5 km in meters
+					}},
+				},
+			})
+		}
+	}
+
+	for _, tag := range tags {
+		if tag.Descriptor.Code == "JOB_DETAILS" {
 			for _, listItem := range tag.List {
 				if listItem.Descriptor.Code == "INDUSTRY_TYPE" {
 					query = append(query, bson.E{Key: "business.industry", Value: listItem.Value})
@@ -701,3 +1927,83 @@ func getSearchFilter(payload *searchrequest.SearchRequest) bson.D {
 
 	return query
 }
+
+// getConsent reads the consent artifact of the applicant from the 'CONSENT' tag of a confirm request
+func getConsent(tags []confirmrequest.Tags) (*dbJobApplication.Consent, error) {
+	for _, tag := range tags {
+		if tag.Descriptor.Code != "CONSENT" {
+			continue
+		}
+
+		var (
+			consent   dbJobApplication.Consent
+			timestamp string
+		)
+
+		for _, item := range tag.List {
+			switch item.Code {
+			case "VERSION":
+				consent.Version = item.Value
+			case "SCOPES":
+				for _, scope := range strings.Split(item.Value, ",") {
+					if scope = strings.ToUpper(strings.TrimSpace(scope)); scope != "" {
+						consent.Scopes = append(consent.Scopes, scope)
+					}
+				}
+			case "TIMESTAMP":
+				timestamp = item.Value
+			}
+		}
+
+		if consent.Version == "" {
+			return nil, fmt.Errorf("no consent version found")
+		}
+
+		givenAt, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consent timestamp: %s", timestamp)
+		}
+		consent.GivenAt = givenAt
+
+		return &consent, nil
+	}
+
+	return nil, fmt.Errorf("consent of the applicant is required")
+}
+
+// validateConsent checks the consent is given to the current version of the consent terms, for all their scopes
+func validateConsent(consent *dbJobApplication.Consent, consentTerms *dbBusiness.ConsentTerms) error {
+	if consent.Version != consentTerms.Version {
+		return fmt.Errorf("consent is given to version %s of the consent terms, the current version is %s", consent.Version, consentTerms.Version)
+	}
+
+	for _, scope := range consentTerms.Scopes {
+		if !slices.Contains(consent.Scopes, scope) {
+			return fmt.Errorf("consent is not given for scope: %s", scope)
+		}
+	}
+
+	// allow for the clock skew between the BAP and the adapter
+	if consent.GivenAt.After(time.Now().Add(time.Minute)) {
+		return fmt.Errorf("consent timestamp is in the future")
+	}
+
+	return nil
+}
+
+// isConsentRevocation reports whether the 'CONSENT' tag of an update request revokes the consent
+func isConsentRevocation(tags []updaterequest.Tags) bool {
+	for _, tag := range tags {
+		if tag.Descriptor.Code != "CONSENT" {
+			continue
+		}
+
+		for _, item := range tag.List {
+			if item.Code == "REVOKED" && item.Value == "true" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
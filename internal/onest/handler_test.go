@@ -0,0 +1,559 @@
+package onest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	dbBusiness "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
+	dbJob "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
+	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	confirmrequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/confirm/request"
+	updaterequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/update/request"
+)
+
+func consentTag(items ...confirmrequest.List) confirmrequest.Tags {
+	return confirmrequest.Tags{
+		Descriptor: confirmrequest.TagsDescriptor{Code: "CONSENT"},
+		List:       items,
+	}
+}
+
+func TestGetConsent(t *testing.T) {
+	givenAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		tags    []confirmrequest.Tags
+		want    *dbJobApplication.Consent
+		wantErr bool
+	}{
+		{
+			name: "consent given",
+			tags: []confirmrequest.Tags{
+				{Descriptor: confirmrequest.TagsDescriptor{Code: "OTHER"}},
+				consentTag(
+					confirmrequest.List{Code: "VERSION", Value: "v2"},
+					confirmrequest.List{Code: "SCOPES", Value: " profile, documents ,,"},
+					confirmrequest.List{Code: "TIMESTAMP", Value: "2024-05-01T10:00:00Z"},
+				),
+			},
+			want: &dbJobApplication.Consent{Version: "v2", Scopes: []string{"PROFILE", "DOCUMENTS"}, GivenAt: givenAt},
+		},
+		{
+			name: "consent without scopes",
+			tags: []confirmrequest.Tags{consentTag(
+				confirmrequest.List{Code: "VERSION", Value: "v1"},
+				confirmrequest.List{Code: "TIMESTAMP", Value: "2024-05-01T15:30:00+05:30"},
+			)},
+			want: &dbJobApplication.Consent{Version: "v1", GivenAt: givenAt},
+		},
+		{
+			name:    "no consent tag",
+			tags:    []confirmrequest.Tags{{Descriptor: confirmrequest.TagsDescriptor{Code: "OTHER"}}},
+			wantErr: true,
+		},
+		{
+			name: "missing version",
+			tags: []confirmrequest.Tags{consentTag(
+				confirmrequest.List{Code: "TIMESTAMP", Value: "2024-05-01T10:00:00Z"},
+			)},
+			wantErr: true,
+		},
+		{
+			name: "invalid timestamp",
+			tags: []confirmrequest.Tags{consentTag(
+				confirmrequest.List{Code: "VERSION", Value: "v1"},
+				confirmrequest.List{Code: "TIMESTAMP", Value: "01/05/2024"},
+			)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getConsent(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getConsent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != nil && tt.want != nil {
+				if !got.GivenAt.Equal(tt.want.GivenAt) {
+					t.Fatalf("getConsent() given at %v, want %v", got.GivenAt, tt.want.GivenAt)
+				}
+				got.GivenAt = tt.want.GivenAt
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("getConsent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConsent(t *testing.T) {
+	terms := &dbBusiness.ConsentTerms{Version: "v2", Scopes: []string{"PROFILE", "DOCUMENTS"}}
+
+	tests := []struct {
+		name    string
+		consent *dbJobApplication.Consent
+		wantErr bool
+	}{
+		{
+			name:    "all scopes of the current version",
+			consent: &dbJobApplication.Consent{Version: "v2", Scopes: []string{"DOCUMENTS", "PROFILE", "MARKETING"}, GivenAt: time.Now()},
+		},
+		{
+			name:    "timestamp within the clock skew",
+			consent: &dbJobApplication.Consent{Version: "v2", Scopes: []string{"PROFILE", "DOCUMENTS"}, GivenAt: time.Now().Add(30 * time.Second)},
+		},
+		{
+			name:    "previous version",
+			consent: &dbJobApplication.Consent{Version: "v1", Scopes: []string{"PROFILE", "DOCUMENTS"}, GivenAt: time.Now()},
+			wantErr: true,
+		},
+		{
+			name:    "missing scope",
+			consent: &dbJobApplication.Consent{Version: "v2", Scopes: []string{"PROFILE"}, GivenAt: time.Now()},
+			wantErr: true,
+		},
+		{
+			name:    "timestamp in the future",
+			consent: &dbJobApplication.Consent{Version: "v2", Scopes: []string{"PROFILE", "DOCUMENTS"}, GivenAt: time.Now().Add(time.Hour)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateConsent(tt.consent, terms); (err != nil) != tt.wantErr {
+				t.Fatalf("validateConsent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetApplicationKeysUpdate(t *testing.T) {
+	var (
+		keys         = dbJobApplication.GetApplicantKeys("9876543210", "old@example.com")
+		applied      = &dbJobApplication.JobApplication{ID: "a1", JobID: "j1", ApplicantKeys: keys, ApplicationKeys: dbJobApplication.GetApplicationKeys("j1", keys)}
+		duplicate    = &dbJobApplication.JobApplication{ID: "a2", JobID: "j1", ApplicantKeys: keys}
+		newPhoneKeys = dbJobApplication.ApplicantKeys{Phone: dbJobApplication.GetApplicantKeys("9123456780", "").Phone, Email: keys.Email}
+		newEmailKeys = dbJobApplication.ApplicantKeys{Phone: keys.Phone, Email: dbJobApplication.GetApplicantKeys("", "New@Example.com").Email}
+	)
+
+	update := func(updateTarget string, contact updaterequest.Contact) *updaterequest.UpdateRequest {
+		return &updaterequest.UpdateRequest{Message: updaterequest.Message{
+			UpdateTarget: updateTarget,
+			Order: updaterequest.Order{Fulfillments: []updaterequest.Fulfillments{{
+				Customer: updaterequest.Customer{Contact: contact},
+			}}},
+		}}
+	}
+
+	tests := []struct {
+		name               string
+		jobApplication     *dbJobApplication.JobApplication
+		payload            *updaterequest.UpdateRequest
+		wantApplicantKeys  dbJobApplication.ApplicantKeys
+		wantKeys           []string
+		wantKeysUpdated    bool
+		wantContactUpdated bool
+	}{
+		{
+			name:               "phone updated",
+			jobApplication:     applied,
+			payload:            update("order.fulfillments[0].customer.contact.phone", updaterequest.Contact{Phone: "9123456780"}),
+			wantApplicantKeys:  newPhoneKeys,
+			wantKeys:           dbJobApplication.GetApplicationKeys("j1", newPhoneKeys),
+			wantKeysUpdated:    true,
+			wantContactUpdated: true,
+		},
+		{
+			name:               "email updated",
+			jobApplication:     applied,
+			payload:            update("order.fulfillments[0].customer.contact.email", updaterequest.Contact{Email: "New@Example.com"}),
+			wantApplicantKeys:  newEmailKeys,
+			wantKeys:           dbJobApplication.GetApplicationKeys("j1", newEmailKeys),
+			wantKeysUpdated:    true,
+			wantContactUpdated: true,
+		},
+		{
+			name:              "same contact details",
+			jobApplication:    applied,
+			payload:           update("order.fulfillments[0].customer.contact.phone", updaterequest.Contact{Phone: "9876543210"}),
+			wantApplicantKeys: keys,
+		},
+		{
+			name:           "documents updated",
+			jobApplication: applied,
+			payload: &updaterequest.UpdateRequest{Message: updaterequest.Message{
+				UpdateTarget: "order.fulfillments[0].customer.person.creds",
+				Order: updaterequest.Order{Fulfillments: []updaterequest.Fulfillments{{
+					Customer: updaterequest.Customer{Person: updaterequest.Person{Creds: []updaterequest.Creds{{
+						Descriptor: updaterequest.CredsDescriptor{Name: "RESUME"},
+						URL:        "https://example.com/resume.pdf",
+					}}}},
+				}}},
+			}},
+			wantApplicantKeys: keys,
+		},
+		{
+			name:               "flagged duplicate holding no keys",
+			jobApplication:     duplicate,
+			payload:            update("order.fulfillments[0].customer.contact.phone", updaterequest.Contact{Phone: "9123456780"}),
+			wantApplicantKeys:  newPhoneKeys,
+			wantContactUpdated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := getJobApplicationUpdate(tt.payload, getUpdateTargets(tt.payload.Message.UpdateTarget))
+			if err != nil {
+				t.Fatalf("getJobApplicationUpdate() error = %v", err)
+			}
+
+			applicantKeys := getApplicantKeysUpdate(tt.jobApplication, fields)
+			if applicantKeys != tt.wantApplicantKeys {
+				t.Fatalf("getApplicantKeysUpdate() = %+v, want %+v", applicantKeys, tt.wantApplicantKeys)
+			}
+			if contactUpdated := applicantKeys != tt.jobApplication.ApplicantKeys; contactUpdated != tt.wantContactUpdated {
+				t.Fatalf("contact updated = %v, want %v", contactUpdated, tt.wantContactUpdated)
+			}
+
+			keys, ok := getApplicationKeysUpdate(tt.jobApplication, fields)
+			if ok != tt.wantKeysUpdated || !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Fatalf("getApplicationKeysUpdate() = %v, %v, want %v, %v", keys, ok, tt.wantKeys, tt.wantKeysUpdated)
+			}
+		})
+	}
+}
+
+func TestGetUpdateTargets(t *testing.T) {
+	tests := []struct {
+		name         string
+		updateTarget string
+		want         []string
+	}{
+		{
+			name:         "single target",
+			updateTarget: "order.fulfillments[0].state",
+			want:         []string{"fulfillments.state"},
+		},
+		{
+			name:         "comma separated targets",
+			updateTarget: "order.fulfillments[0].customer.contact.phone, order.fulfillments[].customer.person.creds",
+			want:         []string{"fulfillments.customer.contact.phone", "fulfillments.customer.person.creds"},
+		},
+		{
+			name:         "target without the order prefix",
+			updateTarget: "tags",
+			want:         []string{"tags"},
+		},
+		{
+			name:         "empty targets",
+			updateTarget: " , ",
+			want:         nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getUpdateTargets(tt.updateTarget); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("getUpdateTargets(%q) = %v, want %v", tt.updateTarget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJobApplicationUpdate(t *testing.T) {
+	customer := func(customer updaterequest.Customer) *updaterequest.UpdateRequest {
+		return &updaterequest.UpdateRequest{Message: updaterequest.Message{
+			Order: updaterequest.Order{Fulfillments: []updaterequest.Fulfillments{{Customer: customer}}},
+		}}
+	}
+	creds := func(creds ...updaterequest.Creds) *updaterequest.UpdateRequest {
+		return customer(updaterequest.Customer{Person: updaterequest.Person{Creds: creds}})
+	}
+
+	tests := []struct {
+		name    string
+		payload *updaterequest.UpdateRequest
+		targets []string
+		want    bson.D
+		wantErr bool
+	}{
+		{
+			name:    "phone",
+			payload: customer(updaterequest.Customer{Contact: updaterequest.Contact{Phone: "+91 98765 43210"}}),
+			targets: []string{"fulfillments.customer.contact.phone"},
+			want: bson.D{
+				{Key: "applicant_details.phone", Value: "+91 98765 43210"},
+				{Key: "applicant_keys.phone", Value: dbJobApplication.GetApplicantKeys("9876543210", "").Phone},
+			},
+		},
+		{
+			name:    "email",
+			payload: customer(updaterequest.Customer{Contact: updaterequest.Contact{Email: "A@b.co"}}),
+			targets: []string{"fulfillments.customer.contact.email"},
+			want: bson.D{
+				{Key: "applicant_details.email", Value: "A@b.co"},
+				{Key: "applicant_keys.email", Value: dbJobApplication.GetApplicantKeys("", "a@b.co").Email},
+			},
+		},
+		{
+			name: "creds",
+			payload: creds(
+				updaterequest.Creds{Descriptor: updaterequest.CredsDescriptor{Name: "RESUME"}, URL: "https://r", Type: "application/pdf"},
+				updaterequest.Creds{Descriptor: updaterequest.CredsDescriptor{Name: "PAN_CARD"}, URL: "https://p"},
+			),
+			targets: []string{"fulfillments.customer.person.creds"},
+			want: bson.D{
+				{Key: "applicant_details.documents.resume", Value: dbJobApplication.Document{URL: "https://r", Type: "application/pdf"}},
+				{Key: "applicant_details.documents.pan_card", Value: dbJobApplication.Document{URL: "https://p"}},
+			},
+		},
+		{
+			name:    "targets not whitelisted are skipped",
+			payload: customer(updaterequest.Customer{Contact: updaterequest.Contact{Phone: "9876543210"}}),
+			targets: []string{"fulfillments.customer.contact", "fulfillments.state"},
+			want:    nil,
+		},
+		{
+			name:    "no phone",
+			payload: customer(updaterequest.Customer{}),
+			targets: []string{"fulfillments.customer.contact.phone"},
+			wantErr: true,
+		},
+		{
+			name:    "no email",
+			payload: &updaterequest.UpdateRequest{},
+			targets: []string{"fulfillments.customer.contact.email"},
+			wantErr: true,
+		},
+		{
+			name:    "no creds",
+			payload: creds(),
+			targets: []string{"fulfillments.customer.person.creds"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported cred",
+			payload: creds(updaterequest.Creds{Descriptor: updaterequest.CredsDescriptor{Name: "VOTER_ID"}, URL: "https://v"}),
+			targets: []string{"fulfillments.customer.person.creds"},
+			wantErr: true,
+		},
+		{
+			name:    "cred without url",
+			payload: creds(updaterequest.Creds{Descriptor: updaterequest.CredsDescriptor{Name: "RESUME"}}),
+			targets: []string{"fulfillments.customer.person.creds"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getJobApplicationUpdate(tt.payload, tt.targets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getJobApplicationUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("getJobApplicationUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNewJobApplicationStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		job              dbJob.Job
+		want             dbJobApplication.JobApplicationStatus
+		wantErr          bool
+		wantApplications int
+	}{
+		{
+			name:             "slot available",
+			job:              dbJob.Job{ID: "j1", Openings: 2, Applications: 1},
+			want:             dbJobApplication.JobApplicationStatusApplicationAccepted,
+			wantApplications: 2,
+		},
+		{
+			name:             "last slot under the cap",
+			job:              dbJob.Job{ID: "j1", Openings: 1, ApplicationCap: 3, Applications: 2, Waitlist: true},
+			want:             dbJobApplication.JobApplicationStatusApplicationAccepted,
+			wantApplications: 3,
+		},
+		{
+			name:             "cap reached, waitlisted",
+			job:              dbJob.Job{ID: "j1", Openings: 1, ApplicationCap: 3, Applications: 3, Waitlist: true},
+			want:             dbJobApplication.JobApplicationStatusWaitlisted,
+			wantApplications: 3,
+		},
+		{
+			name:             "cap reached without a waitlist",
+			job:              dbJob.Job{ID: "j1", Openings: 1, ApplicationCap: 3, Applications: 3},
+			wantErr:          true,
+			wantApplications: 3,
+		},
+		{
+			name:             "every opening filled, waitlisted",
+			job:              dbJob.Job{ID: "j1", Openings: 1, Hired: 1, ApplicationCap: 3, Waitlist: true},
+			want:             dbJobApplication.JobApplicationStatusWaitlisted,
+			wantApplications: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients, operator, _ := newMemoryClients(t)
+			if err := clients.JobClient.CreateJob(&tt.job); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := (&Onest{clients: clients}).getNewJobApplicationStatus(tt.job.ID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getNewJobApplicationStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("getNewJobApplicationStatus() = %s, want %s", got, tt.want)
+			}
+
+			job := operator.getJob(tt.job.ID)
+			if job.Applications != tt.wantApplications {
+				t.Fatalf("applications = %d, want %d, from %d", job.Applications, tt.wantApplications, tt.job.Applications)
+			}
+			if job.Hired != tt.job.Hired {
+				t.Fatalf("hired = %d, want %d", job.Hired, tt.job.Hired)
+			}
+		})
+	}
+}
+
+func TestUpdateJobCounters(t *testing.T) {
+	tests := []struct {
+		name             string
+		job              dbJob.Job
+		waitlisted       int
+		from             dbJobApplication.JobApplicationStatus
+		to               dbJobApplication.JobApplicationStatus
+		wantApplications int
+		wantHired        int
+		wantPromoted     []string
+	}{
+		{
+			name:             "withdrawn, the oldest waitlisted application promoted",
+			job:              dbJob.Job{ID: "j1", Openings: 1, ApplicationCap: 2, Applications: 2, Waitlist: true},
+			waitlisted:       2,
+			from:             dbJobApplication.JobApplicationStatusApplicationAccepted,
+			to:               dbJobApplication.JobApplicationStatusCancelled,
+			wantApplications: 2,
+			wantPromoted:     []string{"w1"},
+		},
+		{
+			name:             "withdrawn without a waitlist",
+			job:              dbJob.Job{ID: "j1", Openings: 1, ApplicationCap: 2, Applications: 2},
+			from:             dbJobApplication.JobApplicationStatusAssessmentInProgress,
+			to:               dbJobApplication.JobApplicationStatusCancelled,
+			wantApplications: 1,
+		},
+		{
+			name:             "rejected, every waitlisted application promoted into the slot",
+			job:              dbJob.Job{ID: "j1", Openings: 2, Applications: 2, Waitlist: true},
+			waitlisted:       1,
+			from:             dbJobApplication.JobApplicationStatusOfferExtended,
+			to:               dbJobApplication.JobApplicationStatusApplicationRejected,
+			wantApplications: 2,
+			wantPromoted:     []string{"w1"},
+		},
+		{
+			name:             "offer accepted, a slot freed under the cap",
+			job:              dbJob.Job{ID: "j1", Openings: 2, ApplicationCap: 2, Applications: 2, Waitlist: true},
+			waitlisted:       2,
+			from:             dbJobApplication.JobApplicationStatusOfferExtended,
+			to:               dbJobApplication.JobApplicationStatusOfferAccepted,
+			wantApplications: 2,
+			wantHired:        1,
+			wantPromoted:     []string{"w1"},
+		},
+		{
+			name:             "offer accepted, the last opening filled",
+			job:              dbJob.Job{ID: "j1", Openings: 1, Applications: 1, Waitlist: true},
+			waitlisted:       1,
+			from:             dbJobApplication.JobApplicationStatusOfferExtended,
+			to:               dbJobApplication.JobApplicationStatusOfferAccepted,
+			wantApplications: 0,
+			wantHired:        1,
+		},
+		{
+			name:             "assessment, the slot kept",
+			job:              dbJob.Job{ID: "j1", Openings: 1, Applications: 1, Waitlist: true},
+			waitlisted:       1,
+			from:             dbJobApplication.JobApplicationStatusApplicationAccepted,
+			to:               dbJobApplication.JobApplicationStatusAssessmentInProgress,
+			wantApplications: 1,
+		},
+		{
+			name:             "waitlisted application withdrawn",
+			job:              dbJob.Job{ID: "j1", Openings: 1, Applications: 1, Waitlist: true},
+			waitlisted:       1,
+			from:             dbJobApplication.JobApplicationStatusWaitlisted,
+			to:               dbJobApplication.JobApplicationStatusCancelled,
+			wantApplications: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients, operator, apiClient := newMemoryClients(t)
+			if err := clients.JobClient.CreateJob(&tt.job); err != nil {
+				t.Fatal(err)
+			}
+
+			// the waitlisted applications are stored newest first, w1 is the oldest
+			for i := tt.waitlisted; i >= 1; i-- {
+				if err := clients.JobApplicationClient.CreateJobApplication(&dbJobApplication.JobApplication{
+					ID:           fmt.Sprintf("w%d", i),
+					JobID:        tt.job.ID,
+					Status:       dbJobApplication.JobApplicationStatusWaitlisted,
+					BecknContext: &dbJobApplication.BecknContext{BapURI: fmt.Sprintf("https://bap%d", i)},
+					CreatedAt:    time.Now().Add(time.Duration(i) * time.Minute),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := (&Onest{clients: clients}).UpdateJobCounters(tt.job.ID, tt.from, tt.to); err != nil {
+				t.Fatalf("UpdateJobCounters() error = %v", err)
+			}
+
+			job := operator.getJob(tt.job.ID)
+			if job.Applications != tt.wantApplications || job.Hired != tt.wantHired {
+				t.Fatalf("applications, hired = %d, %d, want %d, %d, from %d, %d",
+					job.Applications, job.Hired, tt.wantApplications, tt.wantHired, tt.job.Applications, tt.job.Hired)
+			}
+
+			var wantCallbacks []string
+			for i := 1; i <= tt.waitlisted; i++ {
+				id := fmt.Sprintf("w%d", i)
+
+				want := dbJobApplication.JobApplicationStatusWaitlisted
+				if slices.Contains(tt.wantPromoted, id) {
+					want = dbJobApplication.JobApplicationStatusApplicationAccepted
+					wantCallbacks = append(wantCallbacks, fmt.Sprintf("https://bap%d/on_status", i))
+				}
+
+				if got := operator.getJobApplication(id).Status; got != want {
+					t.Fatalf("status of %s = %s, want %s", id, got, want)
+				}
+			}
+
+			if !slices.Equal(apiClient.urls, wantCallbacks) {
+				t.Fatalf("callbacks = %v, want %v", apiClient.urls, wantCallbacks)
+			}
+		})
+	}
+}
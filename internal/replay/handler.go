@@ -0,0 +1,537 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/onest"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	dbMessageArchive "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/message-archive"
+)
+
+// ackBody is returned to the adapter for the replayed callbacks
+const ackBody = `{"message":{"ack":{"status":"ACK"}}}`
+
+// ignoredFields differ on every exchange, so they are left out of the diffs
+var ignoredFields = []string{"context.timestamp", "context.bap_uri"}
+
+type Interface interface {
+	LoadTransaction(transactionID string) ([]dbMessageArchive.Message, error)
+	LoadFile(path string) ([]dbMessageArchive.Message, error)
+	Replay(messages []dbMessageArchive.Message, out io.Writer) error
+}
+
+// Options configures how the messages are replayed, they are replayed directly through the onest
+// handlers with the callbacks captured in memory unless a target adapter is provided
+type Options struct {
+	// Target is the base url of a running adapter to replay the messages against
+	Target string
+	// CallbackAddr is the address to capture the callbacks of the target adapter on
+	CallbackAddr string
+	// Wait is how long to wait for the callback of a message from the target adapter
+	Wait time.Duration
+}
+
+type Replay struct {
+	clients *clients.Clients
+	options Options
+}
+
+func NewReplay(clients *clients.Clients, options Options) Interface {
+	return &Replay{
+		clients: clients,
+		options: options,
+	}
+}
+
+// callback is an on_* payload sent by the adapter while processing a replayed message
+type callback struct {
+	URL  string
+	Body []byte
+}
+
+// LoadTransaction returns the archived messages of a transaction, in the order they were exchanged
+func (r *Replay) LoadTransaction(transactionID string) ([]dbMessageArchive.Message, error) {
+	messages, err := r.clients.MessageArchiveClient.ListMessages(bson.D{{Key: "transaction_id", Value: transactionID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages of transaction %s, %v", transactionID, err)
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages found for transaction %s", transactionID)
+	}
+
+	return messages, nil
+}
+
+// LoadFile returns the messages of a JSONL file, a line is either an archived message, as stored or as
+// returned by the transaction timeline api, or a bare beckn request which is taken as an inbound message
+func (r *Replay) LoadFile(path string) ([]dbMessageArchive.Message, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s, %v", path, err)
+	}
+	defer file.Close()
+
+	var (
+		messages []dbMessageArchive.Message
+		scanner  = bufio.NewScanner(file)
+		line     int
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line++
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		message, err := parseMessage(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse line %d of %s, %v", line, path, err)
+		}
+
+		messages = append(messages, *message)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s, %v", path, err)
+	}
+
+	return messages, nil
+}
+
+// Replay replays the inbound messages in order and reports their acks and callbacks, along with their
+// diffs from the archived outbound messages
+func (r *Replay) Replay(messages []dbMessageArchive.Message, out io.Writer) error {
+	var (
+		inbound  []dbMessageArchive.Message
+		outbound []dbMessageArchive.Message
+	)
+
+	for _, message := range messages {
+		if message.Direction == dbMessageArchive.DirectionOutbound {
+			outbound = append(outbound, message)
+		} else {
+			inbound = append(inbound, message)
+		}
+	}
+
+	if len(inbound) == 0 {
+		return fmt.Errorf("no inbound messages to replay")
+	}
+
+	replay := r.replayDirect
+	if r.options.Target != "" {
+		capture, err := r.startCapture()
+		if err != nil {
+			return err
+		}
+		defer capture.close()
+
+		replay = capture.replay
+	}
+
+	for _, message := range inbound {
+		fmt.Fprintf(out, "=> %s %s (transaction %s)\n", message.Action, message.MessageID, message.TransactionID)
+
+		ack, callbacks, err := replay(&message)
+		if err != nil {
+			fmt.Fprintf(out, "   failed to replay, %v\n", err)
+			continue
+		}
+
+		fmt.Fprintf(out, "   ack: %s\n", ack)
+		if message.ResponseBody != "" {
+			writeDiff(out, "   ", []byte(message.ResponseBody), ack)
+		}
+
+		if len(callbacks) == 0 {
+			fmt.Fprintf(out, "   no callback\n")
+		}
+
+		for _, callback := range callbacks {
+			fmt.Fprintf(out, "   <= %s\n", callback.URL)
+			fmt.Fprintf(out, "      %s\n", callback.Body)
+
+			original := takeOriginal(&outbound, callbackAction(callback.Body), message.MessageID)
+			if original == nil {
+				fmt.Fprintf(out, "      no archived callback to compare with\n")
+				continue
+			}
+
+			writeDiff(out, "      ", []byte(original.Body), callback.Body)
+		}
+	}
+
+	return nil
+}
+
+// replayDirect replays a message through the onest handlers, with the callbacks captured by a stub api client
+func (r *Replay) replayDirect(message *dbMessageArchive.Message) ([]byte, []callback, error) {
+	var (
+		stub        = &stubAPIClient{}
+		stubClients = *r.clients
+		body        = io.NopCloser(strings.NewReader(message.Body))
+		ack         interface{}
+	)
+
+	stubClients.ApiClient = stub
+	handler := onest.NewOnestClient(&stubClients)
+
+	switch message.Action {
+	case "search":
+		payload, searchAck := handler.SendJobsAck(body)
+		if ack = searchAck; searchAck.Error == nil {
+			handler.SendJobs(payload)
+		}
+	case "select":
+		payload, selectAck := handler.SendJobFulfillmentAck(body)
+		if ack = selectAck; selectAck.Error == nil {
+			handler.SendJobFulfillment(payload)
+		}
+	case "init":
+		payload, initAck := handler.InitializeJobApplicationAck(body)
+		if ack = initAck; initAck.Error == nil {
+			handler.InitializeJobApplication(payload)
+		}
+	case "confirm":
+		payload, initJobApplication, confirmAck := handler.ConfirmJobApplicationAck(body)
+		if ack = confirmAck; confirmAck.Error == nil {
+			handler.ConfirmJobApplication(payload, initJobApplication)
+		}
+	case "status":
+		payload, statusAck := handler.JobApplicationStatusAck(body)
+		if ack = statusAck; statusAck.Error == nil {
+			handler.JobApplicationStatus(payload)
+		}
+	case "cancel":
+		payload, cancelAck := handler.WithdrawJobApplicationAck(body)
+		if ack = cancelAck; cancelAck.Error == nil {
+			handler.WithdrawJobApplication(payload)
+		}
+	case "update":
+		payload, jobApplication, updateAck := handler.UpdateJobApplicationAck(body)
+		if ack = updateAck; updateAck.Error == nil {
+			handler.UpdateJobApplication(payload, jobApplication)
+		}
+	case "rating":
+		payload, ratingAck := handler.SubmitRatingAck(body)
+		if ack = ratingAck; ratingAck.Error == nil {
+			handler.SubmitRating(payload)
+		}
+	case "support":
+		payload, supportAck := handler.SendSupportAck(body)
+		if ack = supportAck; supportAck.Error == nil {
+			handler.SendSupport(payload)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported action %s", message.Action)
+	}
+
+	data, err := json.Marshal(ack)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal ack, %v", err)
+	}
+
+	return data, stub.callbacks, nil
+}
+
+// stubAPIClient captures the callbacks instead of sending them, and acks them
+type stubAPIClient struct {
+	callbacks []callback
+}
+
+func (s *stubAPIClient) ApiCall(ctx context.Context, request interface{}, url string, response interface{}, method string) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	s.callbacks = append(s.callbacks, callback{URL: url, Body: data})
+
+	if response != nil {
+		return json.Unmarshal([]byte(ackBody), response)
+	}
+
+	return nil
+}
+
+// capture receives the callbacks of the target adapter, the bap_uri of the replayed messages is
+// pointed to it
+type capture struct {
+	target    string
+	bapURI    string
+	wait      time.Duration
+	server    *http.Server
+	callbacks chan callback
+}
+
+func (r *Replay) startCapture() (*capture, error) {
+	listener, err := net.Listen("tcp", r.options.CallbackAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s for callbacks, %v", r.options.CallbackAddr, err)
+	}
+
+	c := &capture{
+		target:    strings.TrimSuffix(r.options.Target, "/"),
+		bapURI:    "http://" + listener.Addr().String(),
+		wait:      r.options.Wait,
+		callbacks: make(chan callback, 16),
+	}
+
+	c.server = &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			c.callbacks <- callback{URL: req.URL.String(), Body: body}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(ackBody))
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		_ = c.server.Serve(listener)
+	}()
+
+	return c, nil
+}
+
+// replay sends a message to the target adapter and waits for its callback
+func (c *capture) replay(message *dbMessageArchive.Message) ([]byte, []callback, error) {
+	var request map[string]interface{}
+	if err := json.Unmarshal([]byte(message.Body), &request); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse message body, %v", err)
+	}
+
+	if context, ok := request["context"].(map[string]interface{}); ok {
+		context["bap_uri"] = c.bapURI
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal message body, %v", err)
+	}
+
+	resp, err := http.Post(c.target+"/"+message.Action, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to send message, %v", err)
+	}
+	defer resp.Body.Close()
+
+	ack, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read ack, %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ack, nil, nil
+	}
+
+	// the late callbacks of the earlier messages are skipped
+	timeout := time.After(c.wait)
+	for {
+		select {
+		case received := <-c.callbacks:
+			if callbackMessageID(received.Body) == message.MessageID {
+				return ack, []callback{received}, nil
+			}
+		case <-timeout:
+			return ack, nil, nil
+		}
+	}
+}
+
+func (c *capture) close() {
+	_ = c.server.Close()
+}
+
+// parseMessage parses a line of a replay file
+func parseMessage(data []byte) (*dbMessageArchive.Message, error) {
+	var line struct {
+		dbMessageArchive.Message
+		Body         json.RawMessage `json:"body"`
+		ResponseBody json.RawMessage `json:"responseBody"`
+		Context      *struct {
+			Action        string `json:"action"`
+			TransactionID string `json:"transaction_id"`
+			MessageID     string `json:"message_id"`
+		} `json:"context"`
+	}
+
+	if err := json.Unmarshal(data, &line); err != nil {
+		return nil, err
+	}
+
+	// a bare beckn request
+	if line.Context != nil {
+		return &dbMessageArchive.Message{
+			TransactionID: line.Context.TransactionID,
+			MessageID:     line.Context.MessageID,
+			Action:        line.Context.Action,
+			Direction:     dbMessageArchive.DirectionInbound,
+			Body:          string(data),
+		}, nil
+	}
+
+	if len(line.Body) == 0 {
+		return nil, fmt.Errorf("neither a beckn request nor an archived message")
+	}
+
+	message := line.Message
+	message.Body = getString(line.Body)
+	message.ResponseBody = getString(line.ResponseBody)
+
+	return &message, nil
+}
+
+// getString returns a json string as is, and any other json value as its encoding
+func getString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	return string(raw)
+}
+
+// callbackContext is the part of a callback payload identifying it
+type callbackContext struct {
+	Context struct {
+		Action    string `json:"action"`
+		MessageID string `json:"message_id"`
+	} `json:"context"`
+}
+
+// callbackAction returns the action of a callback payload
+func callbackAction(body []byte) string {
+	var payload callbackContext
+	_ = json.Unmarshal(body, &payload)
+
+	return payload.Context.Action
+}
+
+// callbackMessageID returns the message id of a callback payload
+func callbackMessageID(body []byte) string {
+	var payload callbackContext
+	_ = json.Unmarshal(body, &payload)
+
+	return payload.Context.MessageID
+}
+
+// takeOriginal removes and returns the archived callback of an action in reply to a message, or else
+// the first one of the action
+func takeOriginal(outbound *[]dbMessageArchive.Message, action, messageID string) *dbMessageArchive.Message {
+	index := slices.IndexFunc(*outbound, func(message dbMessageArchive.Message) bool {
+		return message.Action == action && message.MessageID == messageID
+	})
+	if index < 0 {
+		index = slices.IndexFunc(*outbound, func(message dbMessageArchive.Message) bool {
+			return message.Action == action
+		})
+	}
+	if index < 0 {
+		return nil
+	}
+
+	original := (*outbound)[index]
+	*outbound = slices.Delete(*outbound, index, index+1)
+
+	return &original
+}
+
+// writeDiff writes the fields which differ between the original and the replayed json documents
+func writeDiff(out io.Writer, indent string, original, replayed []byte) {
+	var originalDocument, replayedDocument interface{}
+	if err := json.Unmarshal(original, &originalDocument); err != nil {
+		fmt.Fprintf(out, "%sfailed to parse the original, %v\n", indent, err)
+		return
+	}
+	if err := json.Unmarshal(replayed, &replayedDocument); err != nil {
+		fmt.Fprintf(out, "%sfailed to parse the replayed, %v\n", indent, err)
+		return
+	}
+
+	originalFields, replayedFields := map[string]interface{}{}, map[string]interface{}{}
+	flatten(originalFields, "", originalDocument)
+	flatten(replayedFields, "", replayedDocument)
+
+	var fields []string
+	for field := range originalFields {
+		fields = append(fields, field)
+	}
+	for field := range replayedFields {
+		if _, ok := originalFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var diffs int
+	for _, field := range fields {
+		if slices.Contains(ignoredFields, field) {
+			continue
+		}
+
+		originalValue, inOriginal := originalFields[field]
+		replayedValue, inReplayed := replayedFields[field]
+
+		switch {
+		case !inOriginal:
+			fmt.Fprintf(out, "%s+ %s: %s\n", indent, field, encode(replayedValue))
+		case !inReplayed:
+			fmt.Fprintf(out, "%s- %s: %s\n", indent, field, encode(originalValue))
+		case !reflect.DeepEqual(originalValue, replayedValue):
+			fmt.Fprintf(out, "%s~ %s: %s -> %s\n", indent, field, encode(originalValue), encode(replayedValue))
+		default:
+			continue
+		}
+		diffs++
+	}
+
+	if diffs == 0 {
+		fmt.Fprintf(out, "%sno diff from the original\n", indent)
+	}
+}
+
+// flatten collects the leaf values of a json document keyed by their paths, for eg. message.order.items[0].id
+func flatten(fields map[string]interface{}, path string, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			if path == "" {
+				flatten(fields, key, v)
+			} else {
+				flatten(fields, path+"."+key, v)
+			}
+		}
+	case []interface{}:
+		for i, v := range value {
+			flatten(fields, fmt.Sprintf("%s[%d]", path, i), v)
+		}
+	default:
+		fields[path] = value
+	}
+}
+
+func encode(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,156 @@
+package retention
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	dbJob "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
+	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
+)
+
+type Interface interface {
+	Purge() error
+}
+
+type Retention struct {
+	clients *clients.Clients
+	source  audit.Source
+}
+
+func NewRetention(clients *clients.Clients, source audit.Source) Interface {
+	return &Retention{
+		clients: clients,
+		source:  source,
+	}
+}
+
+// Purge deletes the terminal job applications and the closed jobs older than their configured retention,
+// the unconfirmed init job applications are expired by the database through a TTL index instead
+func (r *Retention) Purge() error {
+	if retention := config.Config.TerminalJobApplicationRetention; retention > 0 {
+		query := bson.D{
+			{Key: "status", Value: bson.D{{Key: "$in", Value: dbJobApplication.TerminalStatuses}}},
+			{Key: "updated_at", Value: bson.D{{Key: "$lt", Value: time.Now().Add(-retention)}}},
+		}
+
+		count, err := r.purgeJobApplications(query)
+		if err != nil {
+			return fmt.Errorf("failed to purge terminal job applications, %v", err)
+		}
+
+		metrics.ObserveRetentionPurge(database.JobApplicationCollection, count)
+
+		if count > 0 {
+			logrus.Infof("[Retention]: Purged %d terminal job applications older than %s", count, retention)
+		}
+	}
+
+	if retention := config.Config.ClosedJobRetention; retention > 0 {
+		jobs, applications, err := r.purgeClosedJobs(time.Now().Add(-retention))
+		if err != nil {
+			return fmt.Errorf("failed to purge closed jobs, %v", err)
+		}
+
+		metrics.ObserveRetentionPurge(database.JobCollection, jobs)
+		metrics.ObserveRetentionPurge(database.JobApplicationCollection, applications)
+
+		if jobs > 0 {
+			logrus.Infof("[Retention]: Purged %d closed jobs and their %d job applications older than %s", jobs, applications, retention)
+		}
+	}
+
+	return nil
+}
+
+// purgeJobApplications deletes the job applications matching the query along with their references
+// from the jobs and the applicant profiles, and returns the deleted count
+func (r *Retention) purgeJobApplications(query bson.D) (int64, error) {
+	jobApplications, err := r.clients.JobApplicationClient.ListJobApplication(query)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(jobApplications) == 0 {
+		return 0, nil
+	}
+
+	var jobApplicationIDs []string
+	for _, jobApplication := range jobApplications {
+		jobApplicationIDs = append(jobApplicationIDs, jobApplication.ID)
+	}
+
+	var (
+		inJobApplicationIDs = bson.D{{Key: "$in", Value: jobApplicationIDs}}
+		pull                = bson.D{{Key: "$pull", Value: bson.D{{Key: "application_ids", Value: inJobApplicationIDs}}}}
+	)
+
+	// the references are removed first, so a failed purge leaves no dangling references behind
+	if _, err := r.clients.JobClient.UpdateJobs(bson.D{{Key: "application_ids", Value: inJobApplicationIDs}}, pull); err != nil {
+		return 0, fmt.Errorf("failed to remove the job applications from their jobs, %v", err)
+	}
+
+	if err := r.clients.ApplicantProfileClient.UpdateApplicantProfiles(bson.D{{Key: "application_ids", Value: inJobApplicationIDs}}, pull); err != nil {
+		return 0, fmt.Errorf("failed to remove the job applications from their applicant profiles, %v", err)
+	}
+
+	deleted, err := r.clients.JobApplicationClient.DeleteJobApplications(bson.D{{Key: "id", Value: inJobApplicationIDs}})
+	if err != nil {
+		return 0, err
+	}
+
+	auditor := audit.NewAudit(r.clients)
+	for _, jobApplication := range jobApplications {
+		auditor.Record(r.source, dbAudit.ActionJobApplicationPurged, dbAudit.Target{JobID: jobApplication.JobID, JobApplicationID: jobApplication.ID}, &jobApplication, nil)
+	}
+
+	return deleted, nil
+}
+
+// purgeClosedJobs deletes the jobs closed before the given time along with all their job applications,
+// and returns the deleted jobs and job applications counts
+func (r *Retention) purgeClosedJobs(closedBefore time.Time) (int64, int64, error) {
+	jobs, err := r.clients.JobClient.ListJobs(bson.D{
+		{Key: "status", Value: dbJob.JobStatusClosed},
+		{Key: "closure.closed_at", Value: bson.D{{Key: "$lt", Value: closedBefore}}},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(jobs) == 0 {
+		return 0, 0, nil
+	}
+
+	var jobIDs []string
+	for _, job := range jobs {
+		jobIDs = append(jobIDs, job.ID)
+	}
+
+	inJobIDs := bson.D{{Key: "$in", Value: jobIDs}}
+
+	applications, err := r.purgeJobApplications(bson.D{{Key: "job_id", Value: inJobIDs}})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deleted, err := r.clients.JobClient.DeleteJobs(bson.D{{Key: "id", Value: inJobIDs}})
+	if err != nil {
+		return 0, applications, err
+	}
+
+	auditor := audit.NewAudit(r.clients)
+	for _, job := range jobs {
+		auditor.Record(r.source, dbAudit.ActionJobPurged, dbAudit.Target{BusinessID: job.Business.ID, JobID: job.ID}, &job, nil)
+	}
+
+	return deleted, applications, nil
+}
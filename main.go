@@ -1,13 +1,25 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"os"
 
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/archive"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/audit"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/job"
+	jobApplication "github.com/ONEST-Network/Job-Manager-Adapter/internal/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/internal/retention"
+	apiclient "github.com/ONEST-Network/Job-Manager-Adapter/pkg/api-client"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/log"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/proxy"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/redact"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/scheduler"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/server"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/tracing"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/sirupsen/logrus"
@@ -25,20 +37,64 @@ func main() {
 		logrus.Fatalf("Failed to parse ENVs, %v", err)
 	}
 
+	// set the configured log level and format
+	if err := log.Configure(config.Config.LogLevel, config.Config.LogFormat); err != nil {
+		logrus.Fatalf("Failed to configure logger, %v", err)
+	}
+
+	// mask the personal details in the logs and the archived messages
+	if err := redact.Init(config.Config.RedactFields, config.Config.RedactionKey); err != nil {
+		logrus.Fatalf("Failed to set up redaction, %v", err)
+	}
+	logrus.AddHook(&redact.Hook{})
+
 	// set proxy envs, if provided
 	proxy.SetProxyENVs()
 
+	// set up the tracing, if an exporter is configured
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		logrus.Fatalf("Failed to set up tracing, %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	// load the encryption keys, if provided
+	if err := encryption.Init(config.Config.EncryptionKeyFile); err != nil {
+		logrus.Fatalf("Failed to load encryption keys, %v", err)
+	}
+
 	// Initialize mongodb clients
-	businessClient, jobClient, jobApplicationClient, initJobApplication := server.InitMongoDB()
+	businessClient, jobClient, jobApplicationClient, initJobApplication, ratingClient, applicantProfileClient, auditClient, messageArchiveClient := server.InitMongoDB()
 
 	// Set up clients
-	clients := clients.NewClients(jobClient, businessClient, jobApplicationClient, initJobApplication)
+	clients := clients.NewClients(jobClient, businessClient, jobApplicationClient, initJobApplication, ratingClient, applicantProfileClient, auditClient, messageArchiveClient)
 
-	// initialize the server
-	server := server.SetupServer(clients)
+	// archive the beckn callbacks sent to the BAPs and record their metrics
+	clients.ApiClient = apiclient.NewAPIClient(archive.NewArchive(clients).ArchiveOutbound, metrics.ObserveCallback)
 
-	// start the server
-	if err := server.Run(fmt.Sprintf(":%s", config.Config.HTTPPort)); err != nil {
-		logrus.Fatal(err)
+	// run a maintenance command, if provided, instead of the server
+	if len(os.Args) > 1 {
+		runCommand(clients, os.Args[1], os.Args[2:])
+		return
 	}
+
+	// migrate the jobs created before the openings were tracked, they match no search until then
+	if err := job.NewJob(clients, audit.SystemSource("legacy jobs migration")).MigrateLegacyJobs(); err != nil {
+		logrus.Fatalf("Failed to migrate legacy jobs, %v", err)
+	}
+
+	// expire the job offers left unanswered by the applicants
+	scheduler.Every("offer expiry", config.Config.OfferExpiryCheckInterval, jobApplication.NewJobApplication(clients, audit.SystemSource("offer expiry")).ExpireOffers)
+
+	// purge the data older than its retention
+	scheduler.Every("retention purge", config.Config.RetentionPurgeInterval, retention.NewRetention(clients, audit.SystemSource("retention purge")).Purge)
+
+	// refresh the job and job application gauges
+	scheduler.Every("metrics refresh", config.Config.MetricsRefreshInterval, job.NewJob(clients, audit.SystemSource("metrics refresh")).RefreshMetrics)
+
+	// initialize the server
+	router := server.SetupServer(clients)
+
+	// serve until interrupted or terminated, and then drain the in-flight work
+	server.Serve(router)
 }
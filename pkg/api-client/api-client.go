@@ -2,30 +2,63 @@ package apiclient
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"path"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/tracing"
 )
 
 type Interface interface {
-	ApiCall(request interface{}, url string, response interface{}, method string) error
+	ApiCall(ctx context.Context, request interface{}, url string, response interface{}, method string) error
+}
+
+// Exchange represents a request sent by the api client along with its response
+type Exchange struct {
+	Method         string
+	URL            string
+	RequestHeaders http.Header
+	RequestBody    []byte
+	StatusCode     int
+	ResponseBody   []byte
+	Latency        time.Duration
+	Err            error
 }
 
-type APIClient struct{}
+// Observer is notified of every exchange of the api client, for eg. to archive it
+type Observer func(exchange *Exchange)
 
-func NewAPIClient() Interface {
-	return &APIClient{}
+type APIClient struct {
+	observers []Observer
 }
 
-func (a *APIClient) ApiCall(request interface{}, url string, response interface{}, method string) error {
-	var (
-		data []byte = nil
-		err  error
-	)
+func NewAPIClient(observers ...Observer) Interface {
+	return &APIClient{
+		observers: observers,
+	}
+}
+
+func (a *APIClient) ApiCall(ctx context.Context, request interface{}, url string, response interface{}, method string) (err error) {
+	ctx, span := tracing.Start(ctx, method+" "+getPath(url), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.request.method", method),
+		attribute.String("url.full", url),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	var data []byte = nil
 
 	if request != nil {
 		data, err = json.Marshal(request)
@@ -34,7 +67,29 @@ func (a *APIClient) ApiCall(request interface{}, url string, response interface{
 		}
 	}
 
-	statusCode, body, err := restCall(method, url, data)
+	var (
+		header = http.Header{"Content-Type": []string{"application/json"}}
+		start  = time.Now()
+	)
+
+	tracing.Inject(ctx, header)
+
+	statusCode, body, err := restCall(ctx, method, url, header, data)
+	span.SetAttributes(attribute.Int("http.response.status_code", statusCode))
+
+	for _, observer := range a.observers {
+		observer(&Exchange{
+			Method:         method,
+			URL:            url,
+			RequestHeaders: header,
+			RequestBody:    data,
+			StatusCode:     statusCode,
+			ResponseBody:   body,
+			Latency:        time.Since(start),
+			Err:            err,
+		})
+	}
+
 	if err != nil {
 		return err
 	}
@@ -52,28 +107,62 @@ func (a *APIClient) ApiCall(request interface{}, url string, response interface{
 	return nil
 }
 
-func restCall(method string, urlStr string, payload []byte) (int, []byte, error) {
-	transport := &http.Transport{
-		DisableKeepAlives: true,
-		Proxy:             http.ProxyFromEnvironment,
+// httpClient is shared by the api clients, so the connections to a host are kept alive and reused across
+// the calls, up to the configured number per host
+var httpClient = sync.OnceValue(func() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   10 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          config.Config.HTTPClientMaxIdleConns,
+			MaxIdleConnsPerHost:   config.Config.HTTPClientMaxIdleConnsPerHost,
+			MaxConnsPerHost:       config.Config.HTTPClientMaxConnsPerHost,
+			IdleConnTimeout:       config.Config.HTTPClientIdleConnTimeout,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
 	}
+})
 
-	client := http.Client{
-		Transport: transport,
-		Timeout:   30 * time.Second,
-	}
+// CloseIdleConnections closes the idle connections kept alive by the api clients, on shutdown
+func CloseIdleConnections() {
+	httpClient().CloseIdleConnections()
+}
 
-	defer client.CloseIdleConnections()
+func restCall(ctx context.Context, method string, urlStr string, header http.Header, payload []byte) (int, []byte, error) {
+	// the call is bounded by the timeout of its action, for eg. on_search, within the deadline of the caller
+	ctx, cancel := context.WithTimeout(ctx, getTimeout(urlStr))
+	defer cancel()
 
-	req, err := http.NewRequestWithContext(context.Background(), method, urlStr, bytes.NewBuffer(payload))
+	var (
+		body    = payload
+		gzipped = config.Config.HTTPClientGzip && len(payload) > 0
+	)
+
+	if gzipped {
+		compressed, err := compress(payload)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to compress request, %v", err)
+		}
+
+		body = compressed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, bytes.NewReader(body))
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to create request, %v", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header = header.Clone()
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
-	var resp *http.Response
-	resp, err = client.Do(req)
+	resp, err := httpClient().Do(req)
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to send request, %v", err)
 	}
@@ -82,12 +171,48 @@ func restCall(method string, urlStr string, payload []byte) (int, []byte, error)
 		return 0, nil, errors.New("received nil response")
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	defer resp.Body.Close()
+
+	// the body is read fully so the connection can be reused
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return resp.StatusCode, nil, fmt.Errorf("failed to read response body, %v", err)
 	}
 
-	defer resp.Body.Close()
+	return resp.StatusCode, respBody, nil
+}
+
+// compress gzips a request body
+func compress(payload []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(payload); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// getTimeout returns the timeout of a call by its action, the last segment of its url path
+func getTimeout(urlStr string) time.Duration {
+	if timeout, ok := config.Config.HTTPClientActionTimeouts[path.Base(getPath(urlStr))]; ok {
+		return timeout
+	}
+
+	return config.Config.HTTPClientTimeout
+}
+
+// getPath returns the path of a url, for eg. /on_confirm, to name its spans
+func getPath(urlStr string) string {
+	parsed, err := url.Parse(urlStr)
+	if err != nil || parsed.Path == "" {
+		return "/"
+	}
 
-	return resp.StatusCode, body, nil
+	return parsed.Path
 }
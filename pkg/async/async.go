@@ -0,0 +1,86 @@
+package async
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	mutex   sync.Mutex
+	running = make(map[uint64]string)
+	nextID  uint64
+)
+
+// Go runs a task in the background, the tasks still running at shutdown are waited for by Drain, the
+// name describes the task in case it has to be abandoned, for eg. with the transaction it is processing
+func Go(name string, task func()) {
+	id := start(name)
+
+	go func() {
+		defer done(id)
+		defer Recover(name)
+
+		task()
+	}()
+}
+
+// Run runs a task in the foreground, tracked like the background tasks so Drain waits for it
+func Run(name string, task func()) {
+	id := start(name)
+	defer done(id)
+	defer Recover(name)
+
+	task()
+}
+
+// Drain waits for the running tasks to complete until the context is done, and returns the names of
+// the tasks still running by then
+func Drain(ctx context.Context) []string {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if names := Running(); len(names) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return Running()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Running returns the names of the running tasks
+func Running() []string {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var names []string
+	for _, name := range running {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func start(name string) uint64 {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	nextID++
+	running[nextID] = name
+
+	return nextID
+}
+
+func done(id uint64) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	delete(running, id)
+}
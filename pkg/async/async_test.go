@@ -0,0 +1,136 @@
+package async
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDrain(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   map[string]time.Duration
+		timeout time.Duration
+		want    []string
+	}{
+		{
+			name:    "no task running",
+			timeout: time.Second,
+		},
+		{
+			name:    "tasks completing in time",
+			tasks:   map[string]time.Duration{"a": 10 * time.Millisecond, "b": 50 * time.Millisecond},
+			timeout: time.Second,
+		},
+		{
+			name:    "task still running",
+			tasks:   map[string]time.Duration{"fast": 0, "slow": time.Second},
+			timeout: 200 * time.Millisecond,
+			want:    []string{"slow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, duration := range tt.tasks {
+				Go(name, func() { time.Sleep(duration) })
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			if got := Drain(ctx); !slices.Equal(got, tt.want) {
+				t.Fatalf("Drain() = %v, want %v", got, tt.want)
+			}
+
+			// wait for the abandoned tasks, so they don't leak into the next case
+			Drain(context.Background())
+		})
+	}
+}
+
+func TestRunRecovers(t *testing.T) {
+	tests := []struct {
+		name string
+		task func()
+	}{
+		{name: "task completing", task: func() {}},
+		{name: "task panicking", task: func() { panic("failure") }},
+		{name: "task panicking with nil map", task: func() {
+			var m map[string]int
+			m["a"] = 1
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Run(tt.name, tt.task)
+
+			if running := Running(); slices.Contains(running, tt.name) {
+				t.Fatalf("Running() = %v, the task is still tracked", running)
+			}
+		})
+	}
+}
+
+func TestPoolReserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		workers   int
+		queueSize int
+		want      int
+	}{
+		{name: "workers and queue", workers: 2, queueSize: 3, want: 5},
+		{name: "no queue", workers: 1, queueSize: 0, want: 1},
+		{name: "at least one worker", workers: 0, queueSize: -1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				pool    = NewPool(tt.name, tt.workers, tt.queueSize)
+				release = make(chan struct{})
+				slots   []*Slot
+			)
+
+			for {
+				slot, ok := pool.Reserve()
+				if !ok {
+					break
+				}
+				slots = append(slots, slot)
+			}
+
+			if len(slots) != tt.want {
+				t.Fatalf("Reserve() succeeded %d times, want %d", len(slots), tt.want)
+			}
+
+			// a released slot and the slot of a completed task can be reserved again
+			slots[0].Release()
+			slots[0].Submit(tt.name, func() { t.Error("task submitted in a released slot") })
+			for _, slot := range slots[1:] {
+				slot.Submit(tt.name, func() { <-release })
+			}
+
+			slot, ok := pool.Reserve()
+			if !ok {
+				t.Fatal("Reserve() failed after a slot was released")
+			}
+
+			close(release)
+			slot.Submit(tt.name, func() {})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if running := Drain(ctx); len(running) > 0 {
+				t.Fatalf("Drain() = %v, want the tasks completed", running)
+			}
+
+			if _, ok := pool.Reserve(); !ok {
+				t.Fatal("Reserve() failed after the tasks completed")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package async
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Pool processes tasks on a fixed number of workers, the tasks waiting for a worker are queued up to the
+// queue size and the tasks beyond it are rejected, a slot is reserved before accepting the work so it can
+// be refused upfront
+type Pool struct {
+	name  string
+	slots chan struct{}
+	queue chan queuedTask
+}
+
+type queuedTask struct {
+	id   uint64
+	name string
+	run  func()
+}
+
+// Slot is a place reserved in a pool for a task, it is either submitted or released
+type Slot struct {
+	pool *Pool
+	once sync.Once
+}
+
+var (
+	poolsMutex sync.Mutex
+	pools      = make(map[string]*Pool)
+)
+
+// NewPool starts a pool of workers processing the tasks queued up to the queue size
+func NewPool(name string, workers, queueSize int) *Pool {
+	workers = max(workers, 1)
+	queueSize = max(queueSize, 0)
+
+	pool := &Pool{
+		name:  name,
+		slots: make(chan struct{}, workers+queueSize),
+		queue: make(chan queuedTask, workers+queueSize),
+	}
+
+	for range workers {
+		go pool.work()
+	}
+
+	return pool
+}
+
+// PoolOf returns the pool named, started with the workers and queue size returned by size on first use
+func PoolOf(name string, size func() (workers, queueSize int)) *Pool {
+	poolsMutex.Lock()
+	defer poolsMutex.Unlock()
+
+	pool, ok := pools[name]
+	if !ok {
+		workers, queueSize := size()
+		pool = NewPool(name, workers, queueSize)
+		pools[name] = pool
+	}
+
+	return pool
+}
+
+// Reserve reserves a slot for a task, it returns false when the workers are busy and the queue is full
+func (p *Pool) Reserve() (*Slot, bool) {
+	select {
+	case p.slots <- struct{}{}:
+		return &Slot{pool: p}, true
+	default:
+		return nil, false
+	}
+}
+
+// Submit queues the task for a worker, tracked like the background tasks so Drain waits for it
+func (s *Slot) Submit(name string, task func()) {
+	s.once.Do(func() {
+		// the queue has room for every reserved slot, so this doesn't block
+		s.pool.queue <- queuedTask{id: start(name), name: name, run: task}
+	})
+}
+
+// Release frees the slot when no task was submitted in it, it is a no-op after Submit
+func (s *Slot) Release() {
+	s.once.Do(func() {
+		<-s.pool.slots
+	})
+}
+
+func (p *Pool) work() {
+	for task := range p.queue {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task queuedTask) {
+	defer func() {
+		<-p.slots
+		done(task.id)
+	}()
+	defer Recover(task.name)
+
+	task.run()
+}
+
+// Recover recovers a panic of a task so it doesn't crash the process, it must be deferred by the task
+func Recover(name string) {
+	if r := recover(); r != nil {
+		logrus.WithField("stack", string(debug.Stack())).Errorf("[Async]: Recovered from panic in %s, %v", name, r)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/cancel/request"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/cancel/response"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
 )
 
 func BuildWithdrawJobApplicationResponse(payload *request.CancelRequest, jobApplication *dbJobApplication.JobApplication) *response.CancelResponse {
@@ -31,39 +32,90 @@ func BuildWithdrawJobApplicationResponse(payload *request.CancelRequest, jobAppl
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
 			TTL:       "PT30S",
 		},
-		Message: response.Message{
-			Order: response.Order{
-				ID:     payload.Message.OrderID,
-				Status: "Cancelled",
-				Provider: response.Provider{
-					ID: "1",
+		Message: getCancelMessage(jobApplication),
+	}
+}
+
+// BuildJobApplicationCancellationNotification builds an unsolicited on_cancel for a job application
+// cancelled by the employer, using the beckn context stored at confirm
+func BuildJobApplicationCancellationNotification(jobApplication *dbJobApplication.JobApplication) *response.CancelResponse {
+	return &response.CancelResponse{
+		Context: response.Context{
+			Domain:        jobApplication.BecknContext.Domain,
+			Action:        "on_cancel",
+			Version:       jobApplication.BecknContext.Version,
+			BapID:         jobApplication.BecknContext.BapID,
+			BapURI:        jobApplication.BecknContext.BapURI,
+			BppID:         jobApplication.BecknContext.BppID,
+			BppURI:        jobApplication.BecknContext.BppURI,
+			TransactionID: jobApplication.BecknContext.TransactionID,
+			MessageID:     random.GetRandomString(16),
+			Location: response.Location{
+				City: response.City{
+					Code: jobApplication.BecknContext.City,
+				},
+				Country: response.Country{
+					Code: jobApplication.BecknContext.Country,
 				},
-				Items: []response.Items{
-					{
-						ID:             jobApplication.JobID,
-						FulfillmentIds: []string{"F1", "C1"},
-						Time: response.Time{
-							Range: response.Range{
-								Start: jobApplication.CreatedAt.UTC().Format(time.RFC3339),
-								End:   jobApplication.CreatedAt.Add(time.Hour * 24 * 30).UTC().Format(time.RFC3339),
-							},
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			TTL:       "PT30S",
+		},
+		Message: getCancelMessage(jobApplication),
+	}
+}
+
+func getCancelMessage(jobApplication *dbJobApplication.JobApplication) response.Message {
+	return response.Message{
+		Order: response.Order{
+			ID:     jobApplication.ID,
+			Status: "Cancelled",
+			Provider: response.Provider{
+				ID: "1",
+			},
+			Items: []response.Items{
+				{
+					ID:             jobApplication.JobID,
+					FulfillmentIds: []string{"F1", "C1"},
+					Time: response.Time{
+						Range: response.Range{
+							Start: jobApplication.CreatedAt.UTC().Format(time.RFC3339),
+							End:   jobApplication.CreatedAt.Add(time.Hour * 24 * 30).UTC().Format(time.RFC3339),
 						},
 					},
 				},
-				Fulfillments: []response.Fulfillments{
-					{
-						ID:   "F1",
-						Type: "lead & recruitment",
-						State: response.State{
-							Descriptor: response.Descriptor{
-								Code: "CANCELLED",
-							},
+			},
+			Cancellation: getCancellation(jobApplication),
+			Fulfillments: []response.Fulfillments{
+				{
+					ID:   "F1",
+					Type: "lead & recruitment",
+					State: response.State{
+						Descriptor: response.Descriptor{
+							Code: string(jobApplication.Status),
 						},
 					},
-					{
-						ID: "C1",
-					},
 				},
+				{
+					ID: "C1",
+				},
+			},
+		},
+	}
+}
+
+func getCancellation(jobApplication *dbJobApplication.JobApplication) *response.Cancellation {
+	if jobApplication.Cancellation == nil {
+		return nil
+	}
+
+	return &response.Cancellation{
+		CancelledBy: string(jobApplication.Cancellation.CancelledBy),
+		Reason: response.Reason{
+			ID: jobApplication.Cancellation.ReasonID,
+			Descriptor: response.ReasonDescriptor{
+				Code:      jobApplication.Cancellation.ReasonID,
+				ShortDesc: jobApplication.Cancellation.Reason,
 			},
 		},
 	}
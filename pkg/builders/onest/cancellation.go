@@ -0,0 +1,20 @@
+package onest
+
+import (
+	"sort"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+)
+
+// getCancellationReasonIDs returns the configured cancellation reason ids in a stable order
+func getCancellationReasonIDs() []string {
+	var ids []string
+
+	for id := range config.Config.CancellationReasons {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
@@ -3,11 +3,14 @@ package onest
 import (
 	"time"
 
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+
 	confirmrequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/confirm/request"
 	confirmresponse "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/confirm/response"
 )
 
-func BuildConfirmJobApplicationResponse(payload *confirmrequest.ConfirmRequest) *confirmresponse.ConfirmResponse {
+func BuildConfirmJobApplicationResponse(payload *confirmrequest.ConfirmRequest, jobApplication *dbJobApplication.JobApplication) *confirmresponse.ConfirmResponse {
 	res := confirmresponse.ConfirmResponse{
 		Context: confirmresponse.Context{
 			Domain:        payload.Context.Domain,
@@ -32,18 +35,19 @@ func BuildConfirmJobApplicationResponse(payload *confirmrequest.ConfirmRequest)
 		},
 		Message: confirmresponse.Message{
 			Order: confirmresponse.Order{
-				ID: payload.Message.Order.ID,
+				ID: jobApplication.ID,
 				Provider: confirmresponse.Provider{
 					ID: payload.Message.Order.Provider.ID,
 				},
-				Items: getConfirmItems(payload),
+				Items:             getConfirmItems(payload),
+				CancellationTerms: getConfirmCancellationTerms(),
 				Fulfillments: []confirmresponse.Fulfillments{
 					{
 						ID:   "F1",
 						Type: "lead & recruitment",
 						State: confirmresponse.State{
 							Descriptor: confirmresponse.StateDescriptor{
-								Code: "APPLICATION_ACCEPTED",
+								Code: string(jobApplication.Status),
 							},
 							UpdatedAt: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
 						},
@@ -69,6 +73,48 @@ func BuildConfirmJobApplicationResponse(payload *confirmrequest.ConfirmRequest)
 	return &res
 }
 
+// BuildConfirmJobApplicationErrorResponse builds the on_confirm response of a job application which couldn't
+// be created after it was acked, for eg. when a concurrent confirm of the same applicant created it first
+func BuildConfirmJobApplicationErrorResponse(payload *confirmrequest.ConfirmRequest, code, paths, message string) *confirmresponse.ConfirmResponse {
+	return &confirmresponse.ConfirmResponse{
+		Context: confirmresponse.Context{
+			Domain:        payload.Context.Domain,
+			Action:        "on_confirm",
+			Version:       payload.Context.Version,
+			BapID:         payload.Context.BapID,
+			BapURI:        payload.Context.BapURI,
+			BppID:         payload.Context.BppID,
+			BppURI:        payload.Context.BppURI,
+			TransactionID: payload.Context.TransactionID,
+			MessageID:     payload.Context.MessageID,
+			Location: confirmresponse.Location{
+				City: confirmresponse.City{
+					Code: payload.Context.Location.City.Code,
+				},
+				Country: confirmresponse.Country{
+					Code: payload.Context.Location.Country.Code,
+				},
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			TTL:       "PT30S",
+		},
+		Message: confirmresponse.Message{
+			Order: confirmresponse.Order{
+				ID: payload.Message.Order.ID,
+				Provider: confirmresponse.Provider{
+					ID: payload.Message.Order.Provider.ID,
+				},
+				Items: getConfirmItems(payload),
+			},
+		},
+		Error: &confirmresponse.Error{
+			Code:    code,
+			Paths:   paths,
+			Message: message,
+		},
+	}
+}
+
 func getConfirmItems(payload *confirmrequest.ConfirmRequest) []confirmresponse.Items {
 	var items []confirmresponse.Items
 
@@ -124,3 +170,35 @@ func getConfirmCreds(payload *confirmrequest.ConfirmRequest) []confirmresponse.C
 
 	return creds
 }
+
+func getConfirmCancellationTerms() []confirmresponse.CancellationTerms {
+	var (
+		terms   []confirmresponse.CancellationTerms
+		reasons = confirmresponse.Tags{
+			Descriptor: confirmresponse.TagsDescriptor{
+				Code: "CANCELLATION_REASONS",
+			},
+		}
+	)
+
+	for _, id := range getCancellationReasonIDs() {
+		reasons.List = append(reasons.List, confirmresponse.List{
+			Code:  id,
+			Value: config.Config.CancellationReasons[id],
+		})
+	}
+
+	for _, status := range dbJobApplication.CancellableStatuses {
+		terms = append(terms, confirmresponse.CancellationTerms{
+			FulfillmentState: confirmresponse.FulfillmentState{
+				Descriptor: confirmresponse.StateDescriptor{
+					Code: string(status),
+				},
+			},
+			ReasonRequired: config.Config.CancellationReasonRequired,
+			Tags:           []confirmresponse.Tags{reasons},
+		})
+	}
+
+	return terms
+}
@@ -1,13 +1,18 @@
 package onest
 
 import (
+	"strings"
 	"time"
 
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+	dbBusiness "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
+	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+
 	initrequest "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/init/request"
 	initresponse "github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/init/response"
 )
 
-func BuildInitializeJobApplicationResponse(payload *initrequest.InitRequest) *initresponse.InitResponse {
+func BuildInitializeJobApplicationResponse(payload *initrequest.InitRequest, consentTerms *dbBusiness.ConsentTerms) *initresponse.InitResponse {
 	res := initresponse.InitResponse{
 		Context: initresponse.Context{
 			Domain:        payload.Context.Domain,
@@ -35,7 +40,9 @@ func BuildInitializeJobApplicationResponse(payload *initrequest.InitRequest) *in
 				Provider: initresponse.Provider{
 					ID: payload.Message.Order.Provider.ID,
 				},
-				Items: getInitItems(payload),
+				Items:             getInitItems(payload),
+				CancellationTerms: getInitCancellationTerms(),
+				Tags:              []initresponse.Tags{getInitConsentTerms(consentTerms)},
 				Fulfillments: []initresponse.Fulfillments{
 					{
 						ID:   "F1",
@@ -124,3 +131,59 @@ func getInitCreds(payload *initrequest.InitRequest) []initresponse.Creds {
 
 	return creds
 }
+
+func getInitCancellationTerms() []initresponse.CancellationTerms {
+	var (
+		terms   []initresponse.CancellationTerms
+		reasons = initresponse.Tags{
+			Descriptor: initresponse.TagsDescriptor{
+				Code: "CANCELLATION_REASONS",
+			},
+		}
+	)
+
+	for _, id := range getCancellationReasonIDs() {
+		reasons.List = append(reasons.List, initresponse.List{
+			Code:  id,
+			Value: config.Config.CancellationReasons[id],
+		})
+	}
+
+	for _, status := range dbJobApplication.CancellableStatuses {
+		terms = append(terms, initresponse.CancellationTerms{
+			FulfillmentState: initresponse.FulfillmentState{
+				Descriptor: initresponse.StateDescriptor{
+					Code: string(status),
+				},
+			},
+			ReasonRequired: config.Config.CancellationReasonRequired,
+			Tags:           []initresponse.Tags{reasons},
+		})
+	}
+
+	return terms
+}
+
+// getInitConsentTerms advertises the consent terms of the business, the applicant has to consent to
+// the same version and scopes at confirm
+func getInitConsentTerms(consentTerms *dbBusiness.ConsentTerms) initresponse.Tags {
+	return initresponse.Tags{
+		Descriptor: initresponse.TagsDescriptor{
+			Code: "CONSENT_TERMS",
+		},
+		List: []initresponse.List{
+			{
+				Code:  "VERSION",
+				Value: consentTerms.Version,
+			},
+			{
+				Code:  "URL",
+				Value: consentTerms.URL,
+			},
+			{
+				Code:  "SCOPES",
+				Value: strings.Join(consentTerms.Scopes, ","),
+			},
+		},
+	}
+}
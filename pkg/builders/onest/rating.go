@@ -0,0 +1,73 @@
+package onest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/rating/request"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/rating/response"
+)
+
+// BuildSubmitRatingResponse builds the on_rating response with the outcome of every rating, the errors
+// are those of the ratings not submitted, in the order of the request
+func BuildSubmitRatingResponse(payload *request.RatingRequest, errs []error) *response.RatingResponse {
+	var (
+		ratings []response.Ratings
+		paths   []string
+	)
+
+	for i, rating := range payload.Message.Ratings {
+		outcome := response.Ratings{
+			ID:             rating.ID,
+			RatingCategory: rating.RatingCategory,
+			Value:          rating.Value,
+			Status:         "SUBMITTED",
+		}
+
+		if errs[i] != nil {
+			outcome.Status = "NOT_SUBMITTED"
+			outcome.Reason = errs[i].Error()
+			paths = append(paths, fmt.Sprintf(".message.ratings[%d]", i))
+		}
+
+		ratings = append(ratings, outcome)
+	}
+
+	var ratingError *response.Error
+	if len(paths) > 0 {
+		ratingError = &response.Error{
+			Code:    "10000",
+			Paths:   strings.Join(paths, ","),
+			Message: fmt.Sprintf("%d of %d ratings could not be submitted", len(paths), len(ratings)),
+		}
+	}
+
+	return &response.RatingResponse{
+		Context: response.Context{
+			Domain:        payload.Context.Domain,
+			Action:        "on_rating",
+			Version:       payload.Context.Version,
+			BapID:         payload.Context.BapID,
+			BapURI:        payload.Context.BapURI,
+			BppID:         payload.Context.BppID,
+			BppURI:        payload.Context.BppURI,
+			TransactionID: payload.Context.TransactionID,
+			MessageID:     payload.Context.MessageID,
+			Location: response.Location{
+				City: response.City{
+					Code: payload.Context.Location.City.Code,
+				},
+				Country: response.Country{
+					Code: payload.Context.Location.Country.Code,
+				},
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			TTL:       "PT30S",
+		},
+		Message: response.Message{
+			Ratings: ratings,
+		},
+		Error: ratingError,
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"strconv"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/rating"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/search/request"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/search/response"
 )
@@ -61,6 +64,11 @@ func BuildSearchJobsResponse(clients *clients.Clients, payload *request.SearchRe
 		},
 	}
 
+	ratings, err := getBusinessRatings(clients, jobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business ratings, %v", err)
+	}
+
 	for i, job := range jobs {
 		business := job.Business
 
@@ -96,7 +104,7 @@ func BuildSearchJobsResponse(clients *clients.Clients, payload *request.SearchRe
 			},
 			Quantity: response.Quantity{
 				Available: response.Available{
-					Count: job.Vacancies,
+					Count: job.AvailableSlots(),
 				},
 			},
 			LocationIds: []string{fmt.Sprintf("L%d", i+1)},
@@ -119,6 +127,7 @@ func BuildSearchJobsResponse(clients *clients.Clients, payload *request.SearchRe
 					Email: business.Email,
 					Phone: business.Phone,
 				},
+				Rating: ratings[business.ID],
 			},
 			Tags: []response.Tags{
 				*addTimingTag(&job),
@@ -144,6 +153,35 @@ func BuildSearchJobsResponse(clients *clients.Clients, payload *request.SearchRe
 	return &res, nil
 }
 
+// getBusinessRatings returns the aggregated rating of the businesses hosting the given jobs
+func getBusinessRatings(clients *clients.Clients, jobs []job.Job) (map[string]string, error) {
+	var (
+		businessIDs []string
+		ratings     = make(map[string]string)
+	)
+
+	for _, job := range jobs {
+		businessIDs = append(businessIDs, job.Business.ID)
+	}
+
+	if len(businessIDs) == 0 {
+		return ratings, nil
+	}
+
+	aggregates, err := clients.RatingClient.AggregateRatings(rating.CategoryProvider, "business_id", bson.D{
+		{Key: "business_id", Value: bson.D{{Key: "$in", Value: businessIDs}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, aggregate := range aggregates {
+		ratings[aggregate.ID] = strconv.FormatFloat(aggregate.Average, 'f', 1, 64)
+	}
+
+	return ratings, nil
+}
+
 func addTimingTag(j *job.Job) *response.Tags {
 	return &response.Tags{
 		Descriptor: response.TagsDescriptor{
@@ -1,12 +1,14 @@
 package onest
 
 import (
+	"strconv"
 	"time"
 
 	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
 
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/status/request"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/status/response"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
 )
 
 func BuildJobApplicationStatusResponse(payload *request.StatusRequest, jobApplication *dbJobApplication.JobApplication) *response.StatusResponse {
@@ -32,38 +34,117 @@ func BuildJobApplicationStatusResponse(payload *request.StatusRequest, jobApplic
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
 			TTL:       "PT30S",
 		},
-		Message: response.Message{
-			Order: response.Order{
-				ID:     jobApplication.ID,
-				Status: string(jobApplication.Status),
-				Provider: response.Provider{
-					ID: "1",
+		Message: getStatusMessage(jobApplication),
+	}
+}
+
+// BuildJobApplicationStatusNotification builds an unsolicited on_status for a job application
+// updated by the BPP, using the beckn context stored at confirm
+func BuildJobApplicationStatusNotification(jobApplication *dbJobApplication.JobApplication) *response.StatusResponse {
+	return &response.StatusResponse{
+		Context: response.Context{
+			Domain:        jobApplication.BecknContext.Domain,
+			Action:        "on_status",
+			Version:       jobApplication.BecknContext.Version,
+			BapID:         jobApplication.BecknContext.BapID,
+			BapURI:        jobApplication.BecknContext.BapURI,
+			BppID:         jobApplication.BecknContext.BppID,
+			BppURI:        jobApplication.BecknContext.BppURI,
+			TransactionID: jobApplication.BecknContext.TransactionID,
+			MessageID:     random.GetRandomString(16),
+			Location: response.Location{
+				City: response.City{
+					Code: jobApplication.BecknContext.City,
+				},
+				Country: response.Country{
+					Code: jobApplication.BecknContext.Country,
 				},
-				Items: []response.Items{
-					{
-						ID:             jobApplication.JobID,
-						FulfillmentIds: []string{"F1"},
-						Time: response.Time{
-							Range: response.Range{
-								Start: jobApplication.CreatedAt.UTC().Format(time.RFC3339),
-								End:   jobApplication.CreatedAt.Add(time.Hour * 24 * 30).UTC().Format(time.RFC3339),
-							},
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			TTL:       "PT30S",
+		},
+		Message: getStatusMessage(jobApplication),
+	}
+}
+
+func getStatusMessage(jobApplication *dbJobApplication.JobApplication) response.Message {
+	return response.Message{
+		Order: response.Order{
+			ID:     jobApplication.ID,
+			Status: string(jobApplication.Status),
+			Provider: response.Provider{
+				ID: "1",
+			},
+			Items: []response.Items{
+				{
+					ID:             jobApplication.JobID,
+					FulfillmentIds: []string{"F1"},
+					Time: response.Time{
+						Range: response.Range{
+							Start: jobApplication.CreatedAt.UTC().Format(time.RFC3339),
+							End:   jobApplication.CreatedAt.Add(time.Hour * 24 * 30).UTC().Format(time.RFC3339),
 						},
 					},
+					Tags: getStatusOfferTags(jobApplication),
 				},
-				Fulfillments: []response.Fulfillments{
-					{
-						ID:   "F1",
-						Type: "lead & recruitment",
-						State: response.State{
-							Descriptor: response.Descriptor{
-								Code: string(jobApplication.Status),
-							},
-							UpdatedAt: jobApplication.UpdatedAt.UTC().Format(time.RFC3339),
+			},
+			Fulfillments: []response.Fulfillments{
+				{
+					ID:   "F1",
+					Type: "lead & recruitment",
+					State: response.State{
+						Descriptor: response.Descriptor{
+							Code: string(jobApplication.Status),
 						},
+						UpdatedAt: jobApplication.UpdatedAt.UTC().Format(time.RFC3339),
 					},
 				},
 			},
 		},
 	}
 }
+
+func getStatusOfferTags(jobApplication *dbJobApplication.JobApplication) []response.Tags {
+	if jobApplication.Offer == nil {
+		return nil
+	}
+
+	return []response.Tags{
+		{
+			Descriptor: response.Descriptor{
+				Code: "OFFER_DETAILS",
+			},
+			List: getStatusOfferTagList(jobApplication.Offer),
+		},
+	}
+}
+
+// getStatusOfferTagList returns the OFFER_DETAILS tag list of a job offer
+func getStatusOfferTagList(offer *dbJobApplication.Offer) []response.List {
+	return []response.List{
+		{
+			Code:  "OFFER_STATUS",
+			Value: string(offer.Status),
+		},
+		{
+			Code:  "SALARY",
+			Value: strconv.Itoa(offer.Salary),
+		},
+		{
+			Code:  "JOINING_DATE",
+			Value: offer.JoiningDate.UTC().Format(time.RFC3339),
+		},
+		{
+			Code:  "LOCATION",
+			Value: offer.Location,
+		},
+		{
+			Code:  "EXPIRES_AT",
+			Value: offer.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+		{
+			Code:  "REVISION",
+			Value: strconv.Itoa(offer.Revision),
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package onest
+
+import (
+	"time"
+
+	dbBusiness "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/support/request"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/support/response"
+)
+
+func BuildSendSupportResponse(payload *request.SupportRequest, support *dbBusiness.Support) *response.SupportResponse {
+	return &response.SupportResponse{
+		Context: response.Context{
+			Domain:        payload.Context.Domain,
+			Action:        "on_support",
+			Version:       payload.Context.Version,
+			BapID:         payload.Context.BapID,
+			BapURI:        payload.Context.BapURI,
+			BppID:         payload.Context.BppID,
+			BppURI:        payload.Context.BppURI,
+			TransactionID: payload.Context.TransactionID,
+			MessageID:     payload.Context.MessageID,
+			Location: response.Location{
+				City: response.City{
+					Code: payload.Context.Location.City.Code,
+				},
+				Country: response.Country{
+					Code: payload.Context.Location.Country.Code,
+				},
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			TTL:       "PT30S",
+		},
+		Message: response.Message{
+			Support: response.Support{
+				OrderID: payload.Message.Support.OrderID,
+				RefID:   payload.Message.Support.RefID,
+				Phone:   support.Phone,
+				Email:   support.Email,
+				URL:     support.URL,
+			},
+		},
+	}
+}
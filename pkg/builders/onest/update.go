@@ -0,0 +1,150 @@
+package onest
+
+import (
+	"strconv"
+	"time"
+
+	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/update/request"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/types/payload/onest/update/response"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
+)
+
+func BuildUpdateJobApplicationResponse(payload *request.UpdateRequest, jobApplication *dbJobApplication.JobApplication) *response.UpdateResponse {
+	return &response.UpdateResponse{
+		Context: response.Context{
+			Domain:        payload.Context.Domain,
+			Action:        "on_update",
+			Version:       payload.Context.Version,
+			BapID:         payload.Context.BapID,
+			BapURI:        payload.Context.BapURI,
+			BppID:         payload.Context.BppID,
+			BppURI:        payload.Context.BppURI,
+			TransactionID: payload.Context.TransactionID,
+			MessageID:     payload.Context.MessageID,
+			Location: response.Location{
+				City: response.City{
+					Code: payload.Context.Location.City.Code,
+				},
+				Country: response.Country{
+					Code: payload.Context.Location.Country.Code,
+				},
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			TTL:       "PT30S",
+		},
+		Message: getUpdateMessage(jobApplication),
+	}
+}
+
+// BuildJobApplicationUpdateNotification builds an unsolicited on_update for a job application updated
+// by the employer, for eg. an offer extended, using the beckn context stored at confirm
+func BuildJobApplicationUpdateNotification(jobApplication *dbJobApplication.JobApplication) *response.UpdateResponse {
+	return &response.UpdateResponse{
+		Context: response.Context{
+			Domain:        jobApplication.BecknContext.Domain,
+			Action:        "on_update",
+			Version:       jobApplication.BecknContext.Version,
+			BapID:         jobApplication.BecknContext.BapID,
+			BapURI:        jobApplication.BecknContext.BapURI,
+			BppID:         jobApplication.BecknContext.BppID,
+			BppURI:        jobApplication.BecknContext.BppURI,
+			TransactionID: jobApplication.BecknContext.TransactionID,
+			MessageID:     random.GetRandomString(16),
+			Location: response.Location{
+				City: response.City{
+					Code: jobApplication.BecknContext.City,
+				},
+				Country: response.Country{
+					Code: jobApplication.BecknContext.Country,
+				},
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			TTL:       "PT30S",
+		},
+		Message: getUpdateMessage(jobApplication),
+	}
+}
+
+func getUpdateMessage(jobApplication *dbJobApplication.JobApplication) response.Message {
+	return response.Message{
+		Order: response.Order{
+			ID:     jobApplication.ID,
+			Status: string(jobApplication.Status),
+			Provider: response.Provider{
+				ID: "1",
+			},
+			Items: []response.Items{
+				{
+					ID:             jobApplication.JobID,
+					FulfillmentIds: []string{"F1"},
+					Time: response.Time{
+						Range: response.Range{
+							Start: jobApplication.CreatedAt.UTC().Format(time.RFC3339),
+							End:   jobApplication.CreatedAt.Add(time.Hour * 24 * 30).UTC().Format(time.RFC3339),
+						},
+					},
+					Tags: getUpdateOfferTags(jobApplication),
+				},
+			},
+			Fulfillments: []response.Fulfillments{
+				{
+					ID:   "F1",
+					Type: "lead & recruitment",
+					State: response.State{
+						Descriptor: response.Descriptor{
+							Code: string(jobApplication.Status),
+						},
+						UpdatedAt: jobApplication.UpdatedAt.UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+}
+
+func getUpdateOfferTags(jobApplication *dbJobApplication.JobApplication) []response.Tags {
+	if jobApplication.Offer == nil {
+		return nil
+	}
+
+	return []response.Tags{
+		{
+			Descriptor: response.Descriptor{
+				Code: "OFFER_DETAILS",
+			},
+			List: getUpdateOfferTagList(jobApplication.Offer),
+		},
+	}
+}
+
+// getUpdateOfferTagList returns the OFFER_DETAILS tag list of a job offer
+func getUpdateOfferTagList(offer *dbJobApplication.Offer) []response.List {
+	return []response.List{
+		{
+			Code:  "OFFER_STATUS",
+			Value: string(offer.Status),
+		},
+		{
+			Code:  "SALARY",
+			Value: strconv.Itoa(offer.Salary),
+		},
+		{
+			Code:  "JOINING_DATE",
+			Value: offer.JoiningDate.UTC().Format(time.RFC3339),
+		},
+		{
+			Code:  "LOCATION",
+			Value: offer.Location,
+		},
+		{
+			Code:  "EXPIRES_AT",
+			Value: offer.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+		{
+			Code:  "REVISION",
+			Value: strconv.Itoa(offer.Revision),
+		},
+	}
+}
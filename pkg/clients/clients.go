@@ -1,27 +1,63 @@
 package clients
 
 import (
+	"context"
+
 	apiclient "github.com/ONEST-Network/Job-Manager-Adapter/pkg/api-client"
+	dbApplicantProfile "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/applicant-profile"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	dbBusiness "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
 	dbInitJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/init-job-application"
 	dbJob "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
 	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	dbMessageArchive "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/message-archive"
+	dbRating "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/rating"
 )
 
 type Clients struct {
+	// Context is carried by the database operations and api calls, for eg. the trace of a request
+	Context                  context.Context
 	ApiClient                apiclient.Interface
 	JobClient                *dbJob.Dao
 	BusinessClient           *dbBusiness.Dao
 	JobApplicationClient     *dbJobApplication.Dao
 	InitJobApplicationClient *dbInitJobApplication.Dao
+	RatingClient             *dbRating.Dao
+	ApplicantProfileClient   *dbApplicantProfile.Dao
+	AuditClient              *dbAudit.Dao
+	MessageArchiveClient     *dbMessageArchive.Dao
 }
 
-func NewClients(jobClient *dbJob.Dao, businessClient *dbBusiness.Dao, jobApplicationClient *dbJobApplication.Dao, initJobApplicationClient *dbInitJobApplication.Dao) *Clients {
+func NewClients(jobClient *dbJob.Dao, businessClient *dbBusiness.Dao, jobApplicationClient *dbJobApplication.Dao, initJobApplicationClient *dbInitJobApplication.Dao, ratingClient *dbRating.Dao, applicantProfileClient *dbApplicantProfile.Dao, auditClient *dbAudit.Dao, messageArchiveClient *dbMessageArchive.Dao) *Clients {
 	return &Clients{
+		Context:                  context.Background(),
 		ApiClient:                apiclient.NewAPIClient(),
 		JobClient:                jobClient,
 		BusinessClient:           businessClient,
 		JobApplicationClient:     jobApplicationClient,
 		InitJobApplicationClient: initJobApplicationClient,
+		RatingClient:             ratingClient,
+		ApplicantProfileClient:   applicantProfileClient,
+		AuditClient:              auditClient,
+		MessageArchiveClient:     messageArchiveClient,
+	}
+}
+
+// WithContext returns a copy of the clients carrying the given context without its cancellation, so the
+// background processing of a request outlives it
+func (c *Clients) WithContext(ctx context.Context) *Clients {
+	ctx = context.WithoutCancel(ctx)
+
+	return &Clients{
+		Context:                  ctx,
+		ApiClient:                c.ApiClient,
+		JobClient:                c.JobClient.WithContext(ctx),
+		BusinessClient:           c.BusinessClient.WithContext(ctx),
+		JobApplicationClient:     c.JobApplicationClient.WithContext(ctx),
+		InitJobApplicationClient: c.InitJobApplicationClient.WithContext(ctx),
+		RatingClient:             c.RatingClient.WithContext(ctx),
+		ApplicantProfileClient:   c.ApplicantProfileClient.WithContext(ctx),
+		AuditClient:              c.AuditClient.WithContext(ctx),
+		MessageArchiveClient:     c.MessageArchiveClient.WithContext(ctx),
 	}
 }
@@ -1,16 +1,97 @@
 package config
 
+import "time"
+
 type Configuration struct {
 	AllowedOrigins []string `split_words:"true" default:"(.)+.localhost:([0-9]+)?"`
 	HttpProxy      string   `split_words:"true"`
 	HttpsProxy     string   `split_words:"true"`
 	NoProxy        string   `split_words:"true"`
 	HTTPPort       string   `envconfig:"HTTP_PORT" default:"8080"`
+	LogLevel       string   `split_words:"true" default:"info"`
+	LogFormat      string   `split_words:"true" default:"json"` // json or text
 	DbServer       string   `required:"true" split_words:"true"`
 	DbUser         string   `required:"true" split_words:"true"`
 	DbPassword     string   `required:"true" split_words:"true"`
 	BppId          string   `required:"true" split_words:"true"`
 	BppUri         string   `required:"true" split_words:"true"`
+
+	// how long the in-flight requests and background tasks are waited for on shutdown
+	DrainTimeout time.Duration `split_words:"true" default:"25s"`
+
+	// bearer token of the admin APIs, the admin APIs are disabled without it
+	AdminToken string `split_words:"true"`
+
+	// paths of the personal details masked in the logs and the message archive, a path matches any field
+	// it is the suffix of, and the key of the hashes masking them
+	RedactFields []string `split_words:"true" default:"customer.person.name,customer.contact.phone,customer.contact.email,creds[].url"`
+	RedactionKey string   `split_words:"true"`
+
+	// key file of the keys encrypting the personal details of the applicants, encryption is disabled without it
+	EncryptionKeyFile string `split_words:"true"`
+
+	// default support contacts, used for the businesses without their own support contacts
+	SupportPhone string `split_words:"true"`
+	SupportEmail string `split_words:"true"`
+	SupportURL   string `envconfig:"SUPPORT_URL"`
+
+	// default consent terms, used for the businesses without their own consent terms
+	ConsentTermsVersion string   `split_words:"true" default:"1"`
+	ConsentTermsURL     string   `envconfig:"CONSENT_TERMS_URL"`
+	ConsentScopes       []string `split_words:"true" default:"DOCUMENTS"`
+
+	// cancellation reasons advertised to the applicants, as 'id:description' pairs
+	CancellationReasons        map[string]string `split_words:"true" default:"1:Found another job,2:Not interested anymore,3:Job location is not suitable,4:Salary is not suitable,5:Other"`
+	CancellationReasonRequired bool              `split_words:"true" default:"true"`
+
+	// how a second application to a job by the same applicant is handled, one of
+	// 'reject', 'merge' into the earlier application or 'flag' it as a duplicate
+	DuplicateApplicationPolicy string `split_words:"true" default:"reject"`
+
+	// number of applications notified concurrently when a job is closed
+	JobClosureBatchSize int `split_words:"true" default:"50"`
+
+	OfferValidity            time.Duration `split_words:"true" default:"168h"`
+	OfferExpiryCheckInterval time.Duration `split_words:"true" default:"5m"`
+
+	// how long the data is retained, the unconfirmed init job applications expire through a TTL index
+	// and the rest are purged periodically, a zero retention keeps the data forever
+	InitJobApplicationRetention     time.Duration `split_words:"true" default:"1h"`
+	TerminalJobApplicationRetention time.Duration `split_words:"true" default:"0"`
+	ClosedJobRetention              time.Duration `split_words:"true" default:"0"`
+	RetentionPurgeInterval          time.Duration `split_words:"true" default:"1h"`
+	MessageArchiveRetention         time.Duration `split_words:"true" default:"720h"` // expires through a TTL index
+
+	// workers processing the acked beckn requests of each action in the background and the requests queued
+	// for them, the requests beyond are NACKed with a 429, the overrides per action are 'action:number' pairs
+	AsyncWorkers          int            `split_words:"true" default:"8"`
+	AsyncQueueSize        int            `split_words:"true" default:"100"`
+	AsyncActionWorkers    map[string]int `split_words:"true" default:"search:16"`
+	AsyncActionQueueSizes map[string]int `split_words:"true" default:"search:200"`
+
+	// number of acked beckn requests processed in the background beyond which the adapter isn't ready
+	ReadinessMaxAsyncBacklog int64 `split_words:"true" default:"1000"`
+
+	// outbound calls, for eg. the callbacks to the BAPs, over keep-alive connections shared by the calls, the
+	// timeout overrides per action, for eg. 'on_search:10s', are 'action:timeout' pairs and a zero limit is unlimited
+	HTTPClientTimeout             time.Duration            `split_words:"true" default:"30s"`
+	HTTPClientActionTimeouts      map[string]time.Duration `split_words:"true"`
+	HTTPClientMaxIdleConns        int                      `split_words:"true" default:"100"`
+	HTTPClientMaxIdleConnsPerHost int                      `split_words:"true" default:"16"`
+	HTTPClientMaxConnsPerHost     int                      `split_words:"true" default:"64"`
+	HTTPClientIdleConnTimeout     time.Duration            `split_words:"true" default:"90s"`
+	HTTPClientGzip                bool                     `split_words:"true" default:"false"` // compresses the request bodies
+
+	MetricsRefreshInterval time.Duration `split_words:"true" default:"1m"` // of the job and job application gauges
+
+	// BAP ids labelling the callback metrics, the bap_id of the requests isn't authenticated so the callbacks
+	// to the other BAPs share the 'other' label
+	SubscribedBapIds []string `split_words:"true"`
+
+	// exporter of the trace spans, 'otlp' to the OTEL_EXPORTER_OTLP_* endpoint or 'stdout', tracing is disabled without it
+	TracingExporter    string  `split_words:"true"`
+	TracingServiceName string  `split_words:"true" default:"job-manager-adapter"`
+	TracingSampleRatio float64 `split_words:"true" default:"1"`
 }
 
 var Config Configuration
@@ -0,0 +1,270 @@
+package applicantprofile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/random"
+)
+
+type DaoInterface interface {
+	GetApplicantProfile(profileID string) (*ApplicantProfile, error)
+	GetApplicantProfileByKey(key string) (*ApplicantProfile, error)
+	ListApplicantProfiles(query bson.D) ([]ApplicantProfile, error)
+	UpsertApplicantProfile(key string, applicantDetails jobapplication.ApplicantDetails, applicationID string) (*ApplicantProfile, error)
+	UpdateApplicantProfiles(query, update bson.D) error
+	DeleteApplicantProfile(profileID string) error
+}
+
+type Dao struct {
+	collection *mongo.Collection
+	ctx        context.Context
+}
+
+const dbTimeout = 10 * time.Second
+
+func NewApplicantProfileDao(collection *mongo.Collection) *Dao {
+	if err := database.EnsureIndex(collection, "key_unique_index", bson.D{{Key: "key", Value: 1}}, options.Index().SetUnique(true)); err != nil {
+		logrus.Fatalf("Failed to create unique index for %s collection, %v", collection.Name(), err)
+	}
+	return &Dao{
+		collection: collection,
+		ctx:        context.Background(),
+	}
+}
+
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
+// GetApplicantProfile gets an applicant profile from the database
+func (d *Dao) GetApplicantProfile(profileID string) (*ApplicantProfile, error) {
+	return d.getApplicantProfile(bson.D{{Key: "id", Value: profileID}})
+}
+
+// GetApplicantProfileByKey gets the applicant profile with the given key from the database
+func (d *Dao) GetApplicantProfileByKey(key string) (*ApplicantProfile, error) {
+	return d.getApplicantProfile(bson.D{{Key: "key", Value: key}})
+}
+
+// ListApplicantProfiles lists applicant profiles from the database
+func (d *Dao) ListApplicantProfiles(query bson.D) ([]ApplicantProfile, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	cursor, err := database.Operator.List(ctx, d.collection, query)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var profiles []ApplicantProfile
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return nil, err
+	}
+
+	for i := range profiles {
+		if err := open(&profiles[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return profiles, nil
+}
+
+// UpdateApplicantProfiles updates all the applicant profiles matching the query, it must not
+// update the personal details which are encrypted per profile
+func (d *Dao) UpdateApplicantProfiles(query, update bson.D) error {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	if _, err := database.Operator.UpdateMany(ctx, d.collection, query, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteApplicantProfile deletes an applicant profile from the database
+func (d *Dao) DeleteApplicantProfile(profileID string) error {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	if _, err := database.Operator.Delete(ctx, d.collection, bson.D{{Key: "id", Value: profileID}}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertApplicantProfile links a job application to the applicant profile with the given key, and keeps
+// the latest applicant details in the profile, the profile is created on the applicant's first application
+func (d *Dao) UpsertApplicantProfile(key string, applicantDetails jobapplication.ApplicantDetails, applicationID string) (*ApplicantProfile, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	applicantDetails, envelope, err := jobapplication.SealApplicantDetails(applicantDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt applicant details, %v", err)
+	}
+
+	var (
+		now    = time.Now()
+		query  = bson.D{{Key: "key", Value: key}}
+		update = bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "applicant_details", Value: applicantDetails},
+				{Key: "pii", Value: envelope},
+				{Key: "updated_at", Value: now},
+			}},
+			{Key: "$setOnInsert", Value: bson.D{
+				{Key: "id", Value: random.GetRandomString(10)},
+				{Key: "created_at", Value: now},
+			}},
+			{Key: "$addToSet", Value: bson.D{{Key: "application_ids", Value: applicationID}}},
+		}
+	)
+
+	var profile ApplicantProfile
+	if err := database.Operator.UpdateAndReturnDocument(ctx, d.collection, query, update, options.FindOneAndUpdate().SetUpsert(true)).Decode(&profile); err != nil {
+		return nil, err
+	}
+
+	if err := open(&profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+func (d *Dao) getApplicantProfile(query bson.D) (*ApplicantProfile, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	result := database.Operator.Get(ctx, d.collection, query)
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	var profile ApplicantProfile
+	if err := result.Decode(&profile); err != nil {
+		return nil, err
+	}
+
+	if err := open(&profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// ReencryptApplicantProfiles encrypts the applicant profiles stored before encryption was enabled, and
+// re-encrypts those encrypted with a rotated key, the keys are recomputed as blind indexes as well, a
+// profile whose recomputed key is already taken by a profile created since is merged into the latter,
+// the merged profiles are returned with the profiles they were merged into
+func (d *Dao) ReencryptApplicantProfiles() (int, map[string]string, error) {
+	if !encryption.Enabled() {
+		return 0, nil, fmt.Errorf("encryption is not enabled")
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	cursor, err := database.Operator.List(ctx, d.collection, bson.D{{Key: "pii.key_id", Value: bson.D{{Key: "$ne", Value: encryption.ActiveKeyID()}}}})
+	if err != nil {
+		return 0, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var profiles []ApplicantProfile
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		reencrypted int
+		merged      = make(map[string]string)
+	)
+
+	for _, profile := range profiles {
+		if err := open(&profile); err != nil {
+			return reencrypted, merged, err
+		}
+
+		applicantDetails, envelope, err := jobapplication.SealApplicantDetails(profile.ApplicantDetails)
+		if err != nil {
+			return reencrypted, merged, fmt.Errorf("failed to encrypt applicant profile %s, %v", profile.ID, err)
+		}
+
+		var (
+			key    = GetKey(jobapplication.GetApplicantKeys(profile.ApplicantDetails.Phone, profile.ApplicantDetails.Email))
+			query  = bson.D{{Key: "id", Value: profile.ID}}
+			update = bson.D{{Key: "$set", Value: bson.D{
+				{Key: "key", Value: key},
+				{Key: "applicant_details", Value: applicantDetails},
+				{Key: "pii", Value: envelope},
+			}}}
+		)
+
+		updateCtx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+		_, err = database.Operator.Update(updateCtx, d.collection, query, update)
+		cancel()
+
+		if mongo.IsDuplicateKeyError(err) {
+			var into string
+			if into, err = d.mergeApplicantProfile(&profile, key); err == nil {
+				merged[profile.ID] = into
+			}
+		}
+		if err != nil {
+			return reencrypted, merged, fmt.Errorf("failed to store applicant profile %s, %v", profile.ID, err)
+		}
+		reencrypted++
+	}
+
+	return reencrypted, merged, nil
+}
+
+// mergeApplicantProfile moves the job applications of a profile into the profile with the given key,
+// and deletes it, it returns the id of the profile merged into
+func (d *Dao) mergeApplicantProfile(profile *ApplicantProfile, key string) (string, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	var (
+		query  = bson.D{{Key: "key", Value: key}}
+		update = bson.D{{Key: "$addToSet", Value: bson.D{{Key: "application_ids", Value: bson.D{{Key: "$each", Value: profile.ApplicationIDs}}}}}}
+		into   ApplicantProfile
+	)
+
+	if err := database.Operator.UpdateAndReturnDocument(ctx, d.collection, query, update).Decode(&into); err != nil {
+		return "", err
+	}
+
+	if _, err := database.Operator.Delete(ctx, d.collection, bson.D{{Key: "id", Value: profile.ID}}); err != nil {
+		return "", err
+	}
+
+	return into.ID, nil
+}
+
+func open(profile *ApplicantProfile) error {
+	if err := jobapplication.OpenApplicantDetails(&profile.ApplicantDetails, profile.PII); err != nil {
+		return fmt.Errorf("failed to decrypt applicant profile %s, %v", profile.ID, err)
+	}
+	profile.PII = nil
+
+	return nil
+}
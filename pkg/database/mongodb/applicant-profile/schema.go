@@ -0,0 +1,34 @@
+package applicantprofile
+
+import (
+	"time"
+
+	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+)
+
+// ApplicantProfile represents an applicant across their job applications, keyed by the contact shared
+// by the applicant, the phone number if any or else the email address, as the contact isn't verified
+// the profile only links the applications and its details are never shared into other applications
+type ApplicantProfile struct {
+	ID               string                          `bson:"id" json:"id"`
+	Key              string                          `bson:"key" json:"-"`
+	ApplicantDetails jobapplication.ApplicantDetails `bson:"applicant_details" json:"applicantDetails"` // latest details shared by the applicant
+	ApplicationIDs   []string                        `bson:"application_ids" json:"applicationIds"`
+	PII              *encryption.Envelope            `bson:"pii,omitempty" json:"-"` // encrypted personal details of the applicant
+	CreatedAt        time.Time                       `bson:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time                       `bson:"updated_at" json:"updatedAt"`
+}
+
+// GetKey returns the profile key of the applicant with the given normalized contact details,
+// it is empty when the applicant shared neither a phone number nor an email address
+func GetKey(applicantKeys jobapplication.ApplicantKeys) string {
+	switch {
+	case applicantKeys.Phone != "":
+		return "phone:" + applicantKeys.Phone
+	case applicantKeys.Email != "":
+		return "email:" + applicantKeys.Email
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+)
+
+// DaoInterface has no update or delete operations, the audit entries are append-only
+type DaoInterface interface {
+	CreateAuditEntry(entry *AuditEntry) error
+	ListAuditEntries(query bson.D, limit int64) ([]AuditEntry, error)
+}
+
+type Dao struct {
+	collection *mongo.Collection
+	ctx        context.Context
+}
+
+const dbTimeout = 10 * time.Second
+
+func NewAuditDao(collection *mongo.Collection) *Dao {
+	// the audit entries are listed per business, latest first
+	if err := database.EnsureIndex(collection, "business_created_at_index",
+		bson.D{{Key: "target.business_id", Value: 1}, {Key: "created_at", Value: -1}}, nil); err != nil {
+		logrus.Fatalf("Failed to create business index for %s collection, %v", collection.Name(), err)
+	}
+	// and per job application, for the export of the applicant data
+	if err := database.EnsureIndex(collection, "job_application_index",
+		bson.D{{Key: "target.job_application_id", Value: 1}}, options.Index().SetSparse(true)); err != nil {
+		logrus.Fatalf("Failed to create job application index for %s collection, %v", collection.Name(), err)
+	}
+	return &Dao{
+		collection: collection,
+		ctx:        context.Background(),
+	}
+}
+
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
+// CreateAuditEntry appends an audit entry
+func (d *Dao) CreateAuditEntry(entry *AuditEntry) error {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	if _, err := database.Operator.Create(ctx, d.collection, entry); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListAuditEntries lists the latest audit entries matching the query, newest first, a zero limit lists them all
+func (d *Dao) ListAuditEntries(query bson.D, limit int64) ([]AuditEntry, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := database.Operator.List(ctx, d.collection, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,69 @@
+package audit
+
+import "time"
+
+// AuditEntry represents a mutating operation on the business data, the audit entries are append-only
+type AuditEntry struct {
+	ID            string    `bson:"id" json:"id"`
+	Actor         Actor     `bson:"actor" json:"actor"`
+	Action        Action    `bson:"action" json:"action"`
+	Target        Target    `bson:"target" json:"target"`
+	Changes       []Change  `bson:"changes" json:"changes"`
+	CorrelationID string    `bson:"correlation_id" json:"correlationId"` // request id of the employer APIs, message id of the beckn APIs
+	CreatedAt     time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// Actor represents who performed an operation
+type Actor struct {
+	Type ActorType `bson:"type" json:"type"`
+	ID   string    `bson:"id" json:"id"` // employer user id, BAP id, admin user id or the name of the system task
+}
+
+// ActorType represents the kind of actor performing an operation
+type ActorType string
+
+const (
+	ActorTypeEmployer ActorType = "EMPLOYER"
+	ActorTypeBAP      ActorType = "BAP"
+	ActorTypeAdmin    ActorType = "ADMIN"
+	ActorTypeSystem   ActorType = "SYSTEM"
+)
+
+// Target represents the documents an operation was performed on
+type Target struct {
+	BusinessID       string `bson:"business_id" json:"businessId"`
+	JobID            string `bson:"job_id,omitempty" json:"jobId,omitempty"`
+	JobApplicationID string `bson:"job_application_id,omitempty" json:"jobApplicationId,omitempty"`
+	RatingID         string `bson:"rating_id,omitempty" json:"ratingId,omitempty"`
+}
+
+// Change represents a field changed by an operation, the values of the personal details are redacted
+type Change struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// Action represents the kind of operation performed
+type Action string
+
+const (
+	ActionBusinessCreated           Action = "BUSINESS_CREATED"
+	ActionJobCreated                Action = "JOB_CREATED"
+	ActionJobClosed                 Action = "JOB_CLOSED"
+	ActionJobCountersReconciled     Action = "JOB_COUNTERS_RECONCILED"
+	ActionJobPurged                 Action = "JOB_PURGED"
+	ActionJobApplicationInitialized Action = "JOB_APPLICATION_INITIALIZED"
+	ActionJobApplicationCreated     Action = "JOB_APPLICATION_CREATED"
+	ActionJobApplicationMerged      Action = "JOB_APPLICATION_MERGED"
+	ActionJobApplicationUpdated     Action = "JOB_APPLICATION_UPDATED"
+	ActionJobApplicationStatus      Action = "JOB_APPLICATION_STATUS_UPDATED"
+	ActionJobApplicationPromoted    Action = "JOB_APPLICATION_PROMOTED"
+	ActionJobApplicationCancelled   Action = "JOB_APPLICATION_CANCELLED"
+	ActionJobApplicationClosed      Action = "JOB_APPLICATION_CLOSED"
+	ActionJobApplicationErased      Action = "JOB_APPLICATION_ERASED"
+	ActionJobApplicationPurged      Action = "JOB_APPLICATION_PURGED"
+	ActionOfferExtended             Action = "OFFER_EXTENDED"
+	ActionOfferExpired              Action = "OFFER_EXPIRED"
+	ActionRatingSubmitted           Action = "RATING_SUBMITTED"
+)
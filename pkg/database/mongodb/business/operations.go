@@ -16,18 +16,27 @@ type DaoInterface interface {
 
 type Dao struct {
 	collection *mongo.Collection
+	ctx        context.Context
 }
 
 func NewBusinessDao(collection *mongo.Collection) *Dao {
 	return &Dao{
 		collection: collection,
+		ctx:        context.Background(),
 	}
 }
 
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
 const dbTimeout = 10 * time.Second
 
 func (d *Dao) GetBusiness(id string) (*Business, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	var business Business
@@ -39,7 +48,7 @@ func (d *Dao) GetBusiness(id string) (*Business, error) {
 }
 
 func (d *Dao) CreateBusiness(business *Business) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	if _, err := d.collection.InsertOne(ctx, business); err != nil {
@@ -50,7 +59,7 @@ func (d *Dao) CreateBusiness(business *Business) error {
 }
 
 func (d *Dao) ListBusinesses(query bson.D) ([]Business, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	cursor, err := d.collection.Find(ctx, query)
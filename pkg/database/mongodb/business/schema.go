@@ -2,15 +2,33 @@ package business
 
 // Business represents a business in the database
 type Business struct {
-	ID             string   `bson:"id"`
-	Name           string   `bson:"name"`
-	Phone          string   `bson:"phone"`
-	Email          string   `bson:"email"`
-	PictureURLs    []string `bson:"picture_urls"`
-	Description    string   `bson:"description"`
-	GSTIndexNumber string   `bson:"gst_index_number"`
-	Location       Location `bson:"location"`
-	Industry       Industry `bson:"industry"`
+	ID             string       `bson:"id"`
+	Name           string       `bson:"name"`
+	Phone          string       `bson:"phone"`
+	Email          string       `bson:"email"`
+	PictureURLs    []string     `bson:"picture_urls"`
+	Description    string       `bson:"description"`
+	GSTIndexNumber string       `bson:"gst_index_number"`
+	Location       Location     `bson:"location"`
+	Industry       Industry     `bson:"industry"`
+	Support        Support      `bson:"support"`
+	ConsentTerms   ConsentTerms `bson:"consent_terms"`
+}
+
+// ConsentTerms represents the terms the applicants consent to, advertised in on_init, for the
+// business to access their details
+type ConsentTerms struct {
+	Version string   `bson:"version" json:"version"`
+	URL     string   `bson:"url" json:"url"`
+	Scopes  []string `bson:"scopes" json:"scopes"`
+}
+
+// Support represents the support contacts of a business, shared with
+// the applicants through the support api
+type Support struct {
+	Phone string `bson:"phone" json:"phone"`
+	Email string `bson:"email" json:"email"`
+	URL   string `bson:"url" json:"url"`
 }
 
 // Industry represents the industry of a business
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	// expectedIndexes are the indexes ensured by the daos, by collection
+	expectedIndexes = make(map[string][]string)
+	indexesMutex    sync.Mutex
+)
+
+// ExpectIndex records an index ensured by a dao, so its presence is verified by the readiness check
+func ExpectIndex(collection, name string) {
+	indexesMutex.Lock()
+	defer indexesMutex.Unlock()
+
+	if !slices.Contains(expectedIndexes[collection], name) {
+		expectedIndexes[collection] = append(expectedIndexes[collection], name)
+	}
+}
+
+// Ping checks the connection to the database
+func Ping(ctx context.Context) error {
+	if Client == nil {
+		return errors.New("not connected")
+	}
+
+	return Client.Client.Ping(ctx, nil)
+}
+
+// MissingIndexes returns the expected indexes missing from the database, as collection/index
+func MissingIndexes(ctx context.Context) ([]string, error) {
+	if Client == nil {
+		return nil, errors.New("not connected")
+	}
+
+	indexesMutex.Lock()
+	expected := make(map[string][]string, len(expectedIndexes))
+	for collection, names := range expectedIndexes {
+		expected[collection] = slices.Clone(names)
+	}
+	indexesMutex.Unlock()
+
+	var missing []string
+
+	for collection, names := range expected {
+		cursor, err := Client.Database.Collection(collection).Indexes().List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list indexes of %s collection, %v", collection, err)
+		}
+
+		var indexes []bson.M
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return nil, fmt.Errorf("failed to decode indexes of %s collection, %v", collection, err)
+		}
+
+		for _, name := range names {
+			if !slices.ContainsFunc(indexes, func(index bson.M) bool { return index["name"] == name }) {
+				missing = append(missing, collection+"/"+name)
+			}
+		}
+	}
+
+	slices.Sort(missing)
+
+	return missing, nil
+}
@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndex creates an index of a collection unless it already exists, the index is then expected
+// by the readiness check
+func EnsureIndex(collection *mongo.Collection, indexName string, keys bson.D, opts *options.IndexOptions) error {
+	ctx := context.Background()
+
+	index, err := getIndex(ctx, collection, indexName)
+	if err != nil {
+		return err
+	}
+
+	if index == nil {
+		if opts == nil {
+			opts = options.Index()
+		}
+
+		indexModel := mongo.IndexModel{
+			Keys:    keys,
+			Options: opts.SetName(indexName),
+		}
+
+		if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+			return err
+		}
+
+		logrus.Infof("Index %s created for %s collection", indexName, collection.Name())
+	}
+
+	ExpectIndex(collection.Name(), indexName)
+	return nil
+}
+
+// EnsureTTLIndex creates the TTL index expiring the documents of a collection after their creation, or
+// updates its expiry if the retention has changed, a zero expiry drops the index
+func EnsureTTLIndex(collection *mongo.Collection, indexName string, expireSeconds int32) error {
+	ctx := context.Background()
+
+	index, err := getIndex(ctx, collection, indexName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case index == nil && expireSeconds <= 0:
+		return nil
+	case index == nil:
+		indexModel := mongo.IndexModel{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(expireSeconds).SetName(indexName),
+		}
+
+		if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+			return err
+		}
+
+		logrus.Infof("TTL index %s created for %s collection", indexName, collection.Name())
+	case expireSeconds <= 0:
+		if _, err := collection.Indexes().DropOne(ctx, indexName); err != nil {
+			return err
+		}
+
+		logrus.Infof("TTL index %s dropped for %s collection", indexName, collection.Name())
+		return nil
+	default:
+		if expireAfterSeconds, ok := index["expireAfterSeconds"].(int32); ok && expireAfterSeconds == expireSeconds {
+			break
+		}
+
+		command := bson.D{
+			{Key: "collMod", Value: collection.Name()},
+			{Key: "index", Value: bson.D{
+				{Key: "name", Value: indexName},
+				{Key: "expireAfterSeconds", Value: expireSeconds},
+			}},
+		}
+
+		if err := collection.Database().RunCommand(ctx, command).Err(); err != nil {
+			return err
+		}
+
+		logrus.Infof("TTL index %s updated for %s collection, documents expire after %d seconds", indexName, collection.Name(), expireSeconds)
+	}
+
+	ExpectIndex(collection.Name(), indexName)
+	return nil
+}
+
+// getIndex returns the specification of an index of a collection, nil if it doesn't exist
+func getIndex(ctx context.Context, collection *mongo.Collection, indexName string) (bson.M, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var index bson.M
+		if err := cursor.Decode(&index); err != nil {
+			return nil, err
+		}
+		if name, ok := index["name"].(string); ok && name == indexName {
+			return index, nil
+		}
+	}
+
+	return nil, cursor.Err()
+}
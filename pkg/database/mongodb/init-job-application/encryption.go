@@ -0,0 +1,73 @@
+package jobapplication
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+)
+
+func seal(initJobApplication *InitJobApplication) (*InitJobApplication, error) {
+	sealed := *initJobApplication
+
+	details, envelope, err := jobapplication.SealApplicantDetails(initJobApplication.ApplicantDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt applicant details, %v", err)
+	}
+	sealed.ApplicantDetails, sealed.PII = details, envelope
+
+	return &sealed, nil
+}
+
+func open(initJobApplication *InitJobApplication) error {
+	if err := jobapplication.OpenApplicantDetails(&initJobApplication.ApplicantDetails, initJobApplication.PII); err != nil {
+		return fmt.Errorf("failed to decrypt applicant details of %s transaction, %v", initJobApplication.TransactionID, err)
+	}
+	initJobApplication.PII = nil
+
+	return nil
+}
+
+// ReencryptInitJobApplications encrypts the init job applications stored before encryption was enabled,
+// and re-encrypts those encrypted with a rotated key
+func (d *Dao) ReencryptInitJobApplications() (int, error) {
+	if !encryption.Enabled() {
+		return 0, fmt.Errorf("encryption is not enabled")
+	}
+
+	initJobApplications, err := d.ListInitJobApplication(bson.D{{Key: "pii.key_id", Value: bson.D{{Key: "$ne", Value: encryption.ActiveKeyID()}}}})
+	if err != nil {
+		return 0, err
+	}
+
+	var reencrypted int
+
+	for _, initJobApplication := range initJobApplications {
+		sealed, err := seal(&initJobApplication)
+		if err != nil {
+			return reencrypted, fmt.Errorf("failed to encrypt %s transaction, %v", initJobApplication.TransactionID, err)
+		}
+
+		var (
+			query  = bson.D{{Key: "transaction_id", Value: initJobApplication.TransactionID}}
+			update = bson.D{{Key: "$set", Value: bson.D{
+				{Key: "applicant_details", Value: sealed.ApplicantDetails},
+				{Key: "pii", Value: sealed.PII},
+			}}}
+		)
+
+		ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+		_, err = database.Operator.Update(ctx, d.collection, query, update)
+		cancel()
+		if err != nil {
+			return reencrypted, fmt.Errorf("failed to store %s transaction, %v", initJobApplication.TransactionID, err)
+		}
+		reencrypted++
+	}
+
+	return reencrypted, nil
+}
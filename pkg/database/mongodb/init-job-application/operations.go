@@ -7,7 +7,6 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
 )
@@ -22,24 +21,43 @@ type DaoInterface interface {
 
 type Dao struct {
 	collection *mongo.Collection
+	ctx        context.Context
 }
 
 const dbTimeout = 10 * time.Second
 
-func NewInitJobApplicationDao(collection *mongo.Collection) *Dao {
-	if err := ensureTTLIndex(collection, "created_at_ttl_index", 3600); err != nil {
+// NewInitJobApplicationDao returns the dao of the init job applications, the drafts expire after the
+// given retention as the applicants either confirm them or abandon them
+func NewInitJobApplicationDao(collection *mongo.Collection, retention time.Duration) *Dao {
+	if retention < time.Second {
+		logrus.Fatalf("Invalid retention %s for %s collection, the drafts must expire", retention, collection.Name())
+	}
+	if err := database.EnsureTTLIndex(collection, "created_at_ttl_index", int32(retention.Seconds())); err != nil {
 		logrus.Fatalf("Failed to create TTL index for %s collection, %v", collection.Name(), err)
 	}
 	return &Dao{
 		collection: collection,
+		ctx:        context.Background(),
 	}
 }
 
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
 func (d *Dao) CreateInitJobApplication(jobApplication *InitJobApplication) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
-	if _, err := database.Operator.Create(ctx, d.collection, jobApplication); err != nil {
+	sealed, err := seal(jobApplication)
+	if err != nil {
+		return err
+	}
+
+	if _, err := database.Operator.Create(ctx, d.collection, sealed); err != nil {
 		return err
 	}
 
@@ -47,7 +65,7 @@ func (d *Dao) CreateInitJobApplication(jobApplication *InitJobApplication) error
 }
 
 func (d *Dao) GetInitJobApplication(transactionId string) (*InitJobApplication, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	var query = bson.D{{Key: "transaction_id", Value: transactionId}}
@@ -62,11 +80,15 @@ func (d *Dao) GetInitJobApplication(transactionId string) (*InitJobApplication,
 		return nil, err
 	}
 
+	if err := open(&initJobApplication); err != nil {
+		return nil, err
+	}
+
 	return &initJobApplication, nil
 }
 
 func (d *Dao) ListInitJobApplication(query bson.D) ([]InitJobApplication, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	cursor, err := database.Operator.List(ctx, d.collection, query)
@@ -80,11 +102,17 @@ func (d *Dao) ListInitJobApplication(query bson.D) ([]InitJobApplication, error)
 		return nil, err
 	}
 
+	for i := range initJobApplications {
+		if err := open(&initJobApplications[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	return initJobApplications, nil
 }
 
 func (d *Dao) DeleteInitJobApplication(transactionId string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	var query = bson.D{{Key: "transaction_id", Value: transactionId}}
@@ -95,38 +123,3 @@ func (d *Dao) DeleteInitJobApplication(transactionId string) error {
 
 	return nil
 }
-
-func ensureTTLIndex(collection *mongo.Collection, indexName string, expireSeconds int32) error {
-	ctx := context.Background()
-
-	// Check if the index already exists
-	cursor, err := collection.Indexes().List(ctx)
-	if err != nil {
-		return err
-	}
-	defer cursor.Close(ctx)
-
-	for cursor.Next(ctx) {
-		var index bson.M
-		if err := cursor.Decode(&index); err != nil {
-			return err
-		}
-		if name, ok := index["name"].(string); ok && name == indexName {
-			// TTL index already exists
-			return nil
-		}
-	}
-
-	// Create the TTL index
-	indexModel := mongo.IndexModel{
-		Keys:    bson.D{{Key: "created_at", Value: 1}}, // Index on created_at field
-		Options: options.Index().SetExpireAfterSeconds(expireSeconds).SetName(indexName),
-	}
-
-	if _, err = collection.Indexes().CreateOne(ctx, indexModel); err != nil {
-		return err
-	}
-
-	logrus.Infof("TTL index %s created for %s collection", indexName, collection.Name())
-	return nil
-}
@@ -1,37 +1,25 @@
 package jobapplication
 
-import "time"
+import (
+	"time"
 
-type InitJobApplication struct {
-	TransactionID    string           `bson:"transaction_id"`
-	JobID            string           `bson:"job_id"`
-	ApplicantDetails ApplicantDetails `bson:"applicant_details"`
-	CreatedAt        time.Time        `bson:"created_at"`
-}
-
-type ApplicantDetails struct {
-	Name       string     `bson:"name"`
-	Gender     string     `bson:"gender"`
-	Age        int        `bson:"age"`
-	Experience Experience `bson:"experience"`
-	Documents  Documents  `bson:"documents"`
-	Phone      string     `bson:"phone"`
-	Email      string     `bson:"email"`
-}
+	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+)
 
-type Documents struct {
-	PANCard        *Document `bson:"pan_card"`
-	AadharCard     *Document `bson:"aadhar_card"`
-	Passport       *Document `bson:"passport"`
-	DrivingLicense *Document `bson:"driving_license"`
-	Resume         *Document `bson:"resume"`
-}
-
-type Document struct {
-	URL  string `bson:"url"`
-	Type string `bson:"type"`
+type InitJobApplication struct {
+	TransactionID    string                       `bson:"transaction_id" json:"transactionId"`
+	JobID            string                       `bson:"job_id" json:"jobId"`
+	ApplicantDetails ApplicantDetails             `bson:"applicant_details" json:"applicantDetails"`
+	ApplicantKeys    jobapplication.ApplicantKeys `bson:"applicant_keys" json:"-"`
+	CreatedAt        time.Time                    `bson:"created_at" json:"createdAt"`
+	PII              *encryption.Envelope         `bson:"pii,omitempty" json:"-"` // encrypted personal details of the applicant
 }
 
-type Experience struct {
-	Years int `bson:"years"`
-}
+// The applicant details are those of the job application the init job application is confirmed into
+type (
+	ApplicantDetails = jobapplication.ApplicantDetails
+	Documents        = jobapplication.Documents
+	Document         = jobapplication.Document
+	Experience       = jobapplication.Experience
+)
@@ -16,6 +16,10 @@ const (
 	BusinessCollection           = "business"
 	JobApplicationCollection     = "job-application"
 	InitJobApplicationCollection = "init-job-application"
+	RatingCollection             = "rating"
+	ApplicantProfileCollection   = "applicant-profile"
+	AuditCollection              = "audit"
+	MessageArchiveCollection     = "message-archive"
 )
 
 // MongoClient structure contains all the database collections and the instance of the database
@@ -26,6 +30,10 @@ type MongoClient struct {
 	BusinessCollection           *mongo.Collection
 	JobApplicationCollection     *mongo.Collection
 	InitJobApplicationCollection *mongo.Collection
+	RatingCollection             *mongo.Collection
+	ApplicantProfileCollection   *mongo.Collection
+	AuditCollection              *mongo.Collection
+	MessageArchiveCollection     *mongo.Collection
 }
 
 var (
@@ -50,10 +58,23 @@ func NewMongoClient() (*MongoClient, error) {
 		BusinessCollection:           database.Collection(BusinessCollection),
 		JobApplicationCollection:     database.Collection(JobApplicationCollection),
 		InitJobApplicationCollection: database.Collection(InitJobApplicationCollection),
+		RatingCollection:             database.Collection(RatingCollection),
+		ApplicantProfileCollection:   database.Collection(ApplicantProfileCollection),
+		AuditCollection:              database.Collection(AuditCollection),
+		MessageArchiveCollection:     database.Collection(MessageArchiveCollection),
 		Client:                       client,
 	}, nil
 }
 
+// Disconnect closes the connections to the database
+func Disconnect(ctx context.Context) error {
+	if Client == nil {
+		return nil
+	}
+
+	return Client.Client.Disconnect(ctx)
+}
+
 func connect() (*mongo.Client, error) {
 	if config.Config.DbServer == "" || config.Config.DbUser == "" || config.Config.DbPassword == "" {
 		return nil, errors.New("invalid db credentials")
@@ -0,0 +1,321 @@
+package jobapplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/utils/contact"
+)
+
+// PII represents the personal details of an applicant, stored only in an encrypted envelope
+// when encryption is enabled
+type PII struct {
+	Name      string    `bson:"name"`
+	Age       int       `bson:"age"`
+	Phone     string    `bson:"phone"`
+	Email     string    `bson:"email"`
+	Documents Documents `bson:"documents"`
+}
+
+// GetApplicantKeys returns the blind indexes of the normalized contact details of an applicant
+func GetApplicantKeys(phone, email string) ApplicantKeys {
+	return ApplicantKeys{
+		Phone: encryption.BlindIndex(contact.NormalizePhone(phone)),
+		Email: encryption.BlindIndex(contact.NormalizeEmail(email)),
+	}
+}
+
+// GetApplicationKeys returns the keys of an applicant's application to a job, a unique index on them lets
+// a single application per applicant and job hold them, so the concurrent applications of an applicant
+// can't both pass the duplicate check, the flagged duplicates and the cancelled applications hold none
+func GetApplicationKeys(jobID string, applicantKeys ApplicantKeys) []string {
+	var keys []string
+
+	if applicantKeys.Phone != "" {
+		keys = append(keys, jobID+"/phone:"+applicantKeys.Phone)
+	}
+
+	if applicantKeys.Email != "" {
+		keys = append(keys, jobID+"/email:"+applicantKeys.Email)
+	}
+
+	return keys
+}
+
+// SealApplicantDetails moves the personal details out of the applicant details into an encrypted
+// envelope, the details are returned as is when encryption is disabled
+func SealApplicantDetails(details ApplicantDetails) (ApplicantDetails, *encryption.Envelope, error) {
+	if !encryption.Enabled() {
+		return details, nil, nil
+	}
+
+	data, err := bson.Marshal(PII{
+		Name:      details.Name,
+		Age:       details.Age,
+		Phone:     details.Phone,
+		Email:     details.Email,
+		Documents: details.Documents,
+	})
+	if err != nil {
+		return details, nil, err
+	}
+
+	envelope, err := encryption.Seal(data)
+	if err != nil {
+		return details, nil, err
+	}
+
+	details.Name, details.Age, details.Phone, details.Email = "", 0, "", ""
+	details.Documents = Documents{}
+
+	return details, envelope, nil
+}
+
+// OpenApplicantDetails restores the personal details of the applicant from the encrypted envelope,
+// the details stored before encryption was enabled have no envelope
+func OpenApplicantDetails(details *ApplicantDetails, envelope *encryption.Envelope) error {
+	if envelope == nil {
+		return nil
+	}
+
+	data, err := encryption.Open(envelope)
+	if err != nil {
+		return err
+	}
+
+	var pii PII
+	if err := bson.Unmarshal(data, &pii); err != nil {
+		return err
+	}
+
+	details.Name, details.Age, details.Phone, details.Email = pii.Name, pii.Age, pii.Phone, pii.Email
+	details.Documents = pii.Documents
+
+	return nil
+}
+
+func seal(jobApplication *JobApplication) (*JobApplication, error) {
+	sealed := *jobApplication
+
+	details, envelope, err := SealApplicantDetails(jobApplication.ApplicantDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt applicant details, %v", err)
+	}
+	sealed.ApplicantDetails, sealed.PII = details, envelope
+
+	return &sealed, nil
+}
+
+func open(jobApplication *JobApplication) error {
+	if err := OpenApplicantDetails(&jobApplication.ApplicantDetails, jobApplication.PII); err != nil {
+		return fmt.Errorf("failed to decrypt applicant details of job application %s, %v", jobApplication.ID, err)
+	}
+	jobApplication.PII = nil
+
+	return nil
+}
+
+// sealAttempts is the number of times an update of the personal details is attempted, it is attempted
+// again when the details were updated concurrently in between their read and their update
+const sealAttempts = 3
+
+// errConcurrentUpdate is returned when the personal details kept being updated concurrently
+var errConcurrentUpdate = fmt.Errorf("applicant details were updated concurrently %d times", sealAttempts)
+
+// sealUpdate rewrites an update setting the personal details of an applicant, as those are stored
+// only encrypted, the new details are merged into the stored details of the job application, the
+// query is then guarded on the stored envelope so that the update matches nothing instead of
+// overwriting a concurrent update of the details, guarded reports whether it was
+func (d *Dao) sealUpdate(ctx context.Context, query, update bson.D) (sealedQuery, sealedUpdate bson.D, guarded bool, err error) {
+	if !encryption.Enabled() || !setsApplicantDetails(update) {
+		return query, update, false, nil
+	}
+
+	var (
+		rewritten = make(bson.D, 0, len(update))
+		set       bson.D
+		changes   bson.D
+	)
+
+	for _, operator := range update {
+		fields, ok := operator.Value.(bson.D)
+		if operator.Key != "$set" || !ok {
+			rewritten = append(rewritten, operator)
+			continue
+		}
+
+		for _, field := range fields {
+			if field.Key == "applicant_details" || strings.HasPrefix(field.Key, "applicant_details.") {
+				changes = append(changes, field)
+			} else {
+				set = append(set, field)
+			}
+		}
+	}
+
+	result := database.Operator.Get(ctx, d.collection, query)
+	if result.Err() != nil {
+		return nil, nil, false, result.Err()
+	}
+
+	var jobApplication JobApplication
+	if err := result.Decode(&jobApplication); err != nil {
+		return nil, nil, false, err
+	}
+
+	// every envelope has its own ciphertext, the details stored before encryption was enabled have none
+	guard := bson.D{{Key: "id", Value: jobApplication.ID}, {Key: "pii", Value: bson.D{{Key: "$exists", Value: false}}}}
+	if jobApplication.PII != nil {
+		guard = bson.D{{Key: "id", Value: jobApplication.ID}, {Key: "pii.ciphertext", Value: jobApplication.PII.Ciphertext}}
+	}
+
+	if err := open(&jobApplication); err != nil {
+		return nil, nil, false, err
+	}
+
+	for _, change := range changes {
+		if err := setApplicantDetail(&jobApplication.ApplicantDetails, change); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	details, envelope, err := SealApplicantDetails(jobApplication.ApplicantDetails)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to encrypt applicant details, %v", err)
+	}
+
+	set = append(set, bson.E{Key: "applicant_details", Value: details}, bson.E{Key: "pii", Value: envelope})
+
+	return bson.D{{Key: "$and", Value: bson.A{query, guard}}}, append(rewritten, bson.E{Key: "$set", Value: set}), true, nil
+}
+
+// setsApplicantDetails reports whether the update sets any of the applicant details
+func setsApplicantDetails(update bson.D) bool {
+	for _, operator := range update {
+		fields, ok := operator.Value.(bson.D)
+		if operator.Key != "$set" || !ok {
+			continue
+		}
+
+		for _, field := range fields {
+			if field.Key == "applicant_details" || strings.HasPrefix(field.Key, "applicant_details.") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// setApplicantDetail applies a $set of an applicant details field
+func setApplicantDetail(details *ApplicantDetails, field bson.E) error {
+	var ok bool
+
+	switch path := strings.TrimPrefix(field.Key, "applicant_details"); path {
+	case "":
+		*details, ok = field.Value.(ApplicantDetails)
+	case ".name":
+		details.Name, ok = field.Value.(string)
+	case ".gender":
+		details.Gender, ok = field.Value.(string)
+	case ".age":
+		details.Age, ok = field.Value.(int)
+	case ".phone":
+		details.Phone, ok = field.Value.(string)
+	case ".email":
+		details.Email, ok = field.Value.(string)
+	case ".experience":
+		details.Experience, ok = field.Value.(Experience)
+	case ".documents":
+		details.Documents, ok = field.Value.(Documents)
+	default:
+		var document Document
+		if document, ok = field.Value.(Document); ok {
+			ok = setDocument(&details.Documents, strings.TrimPrefix(path, ".documents."), &document)
+		}
+	}
+
+	if !ok {
+		return fmt.Errorf("unsupported update of %s", field.Key)
+	}
+
+	return nil
+}
+
+func setDocument(documents *Documents, name string, document *Document) bool {
+	switch name {
+	case "pan_card":
+		documents.PANCard = document
+	case "aadhar_card":
+		documents.AadharCard = document
+	case "passport":
+		documents.Passport = document
+	case "driving_license":
+		documents.DrivingLicense = document
+	case "resume":
+		documents.Resume = document
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ReencryptJobApplications encrypts the job applications stored before encryption was enabled, and
+// re-encrypts those encrypted with a rotated key, the blind indexes are recomputed as well
+func (d *Dao) ReencryptJobApplications() (int, error) {
+	if !encryption.Enabled() {
+		return 0, fmt.Errorf("encryption is not enabled")
+	}
+
+	jobApplications, err := d.ListJobApplication(bson.D{{Key: "pii.key_id", Value: bson.D{{Key: "$ne", Value: encryption.ActiveKeyID()}}}})
+	if err != nil {
+		return 0, err
+	}
+
+	var reencrypted int
+
+	for _, jobApplication := range jobApplications {
+		details, envelope, err := SealApplicantDetails(jobApplication.ApplicantDetails)
+		if err != nil {
+			return reencrypted, fmt.Errorf("failed to encrypt job application %s, %v", jobApplication.ID, err)
+		}
+
+		var (
+			applicantKeys = GetApplicantKeys(jobApplication.ApplicantDetails.Phone, jobApplication.ApplicantDetails.Email)
+			query         = bson.D{{Key: "id", Value: jobApplication.ID}}
+			set           = bson.D{
+				{Key: "applicant_details", Value: details},
+				{Key: "applicant_keys", Value: applicantKeys},
+				{Key: "pii", Value: envelope},
+			}
+		)
+
+		if len(jobApplication.ApplicationKeys) > 0 {
+			set = append(set, bson.E{Key: "application_keys", Value: GetApplicationKeys(jobApplication.JobID, applicantKeys)})
+		}
+
+		update := bson.D{{Key: "$set", Value: set}}
+
+		if err := d.updateSealed(query, update); err != nil {
+			return reencrypted, fmt.Errorf("failed to store job application %s, %v", jobApplication.ID, err)
+		}
+		reencrypted++
+	}
+
+	return reencrypted, nil
+}
+
+// updateSealed updates a job application with already encrypted details
+func (d *Dao) updateSealed(query, update bson.D) error {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	_, err := database.Operator.Update(ctx, d.collection, query, update)
+	return err
+}
@@ -0,0 +1,75 @@
+package jobapplication
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetApplicationKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		applicantKeys ApplicantKeys
+		want          []string
+	}{
+		{
+			name:          "phone and email",
+			applicantKeys: ApplicantKeys{Phone: "9876543210", Email: "a@b.co"},
+			want:          []string{"j1/phone:9876543210", "j1/email:a@b.co"},
+		},
+		{
+			name:          "phone only",
+			applicantKeys: ApplicantKeys{Phone: "9876543210"},
+			want:          []string{"j1/phone:9876543210"},
+		},
+		{
+			name:          "email only",
+			applicantKeys: ApplicantKeys{Email: "a@b.co"},
+			want:          []string{"j1/email:a@b.co"},
+		},
+		{
+			name: "no contact details",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetApplicationKeys("j1", tt.applicantKeys); !slices.Equal(got, tt.want) {
+				t.Fatalf("GetApplicationKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetApplicantKeys(t *testing.T) {
+	tests := []struct {
+		name         string
+		phone, email string
+		want         ApplicantKeys
+	}{
+		{
+			name:  "country code and separators",
+			phone: "+91 98765-43210",
+			email: " A@B.co ",
+			want:  ApplicantKeys{Phone: "9876543210", Email: "a@b.co"},
+		},
+		{
+			name:  "same applicant, same application keys",
+			phone: "9876543210",
+			email: "a@b.co",
+			want:  ApplicantKeys{Phone: "9876543210", Email: "a@b.co"},
+		},
+		{
+			name: "no contact details",
+			want: ApplicantKeys{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetApplicantKeys(tt.phone, tt.email); got != tt.want {
+				t.Fatalf("GetApplicantKeys(%q, %q) = %+v, want %+v", tt.phone, tt.email, got, tt.want)
+			}
+		})
+	}
+}
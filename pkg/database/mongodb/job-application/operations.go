@@ -2,12 +2,17 @@ package jobapplication
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
 )
 
 type DaoInterface interface {
@@ -15,27 +20,47 @@ type DaoInterface interface {
 	GetJobApplication(jobApplicationID string) (*JobApplication, error)
 	ListJobApplication(query bson.D) ([]JobApplication, error)
 	DeleteJobApplication(applicationID, name string) error
+	DeleteJobApplications(query bson.D) (int64, error)
 	UpdateJobApplication(query, update bson.D) error
+	UpdateJobApplications(query, update bson.D) (int64, error)
 }
 
 type Dao struct {
 	collection *mongo.Collection
+	ctx        context.Context
 }
 
 func NewJobApplicationDao(collection *mongo.Collection) *Dao {
+	if err := database.EnsureIndex(collection, "application_keys_unique_index",
+		bson.D{{Key: "application_keys", Value: 1}}, options.Index().SetUnique(true).SetSparse(true)); err != nil {
+		logrus.Fatalf("Failed to create unique index for %s collection, %v", collection.Name(), err)
+	}
 	return &Dao{
 		collection: collection,
+		ctx:        context.Background(),
 	}
 }
 
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
 const dbTimeout = 10 * time.Second
 
 // CreateJobApplication creates a job application post in the database
 func (d *Dao) CreateJobApplication(jobApplication *JobApplication) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
-	if _, err := database.Operator.Create(ctx, d.collection, jobApplication); err != nil {
+	sealed, err := seal(jobApplication)
+	if err != nil {
+		return err
+	}
+
+	if _, err := database.Operator.Create(ctx, d.collection, sealed); err != nil {
 		return err
 	}
 
@@ -43,7 +68,7 @@ func (d *Dao) CreateJobApplication(jobApplication *JobApplication) error {
 }
 
 func (d *Dao) GetJobApplication(jobApplicationID string) (*JobApplication, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	var query = bson.D{{Key: "id", Value: jobApplicationID}}
@@ -58,11 +83,15 @@ func (d *Dao) GetJobApplication(jobApplicationID string) (*JobApplication, error
 		return nil, err
 	}
 
+	if err := open(&jobApplication); err != nil {
+		return nil, err
+	}
+
 	return &jobApplication, nil
 }
 
 func (d *Dao) ListJobApplication(query bson.D) ([]JobApplication, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	cursor, err := database.Operator.List(ctx, d.collection, query)
@@ -75,11 +104,17 @@ func (d *Dao) ListJobApplication(query bson.D) ([]JobApplication, error) {
 		return nil, err
 	}
 
+	for i := range jobApplications {
+		if err := open(&jobApplications[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	return jobApplications, nil
 }
 
 func (d *Dao) DeleteJobApplication(applicationID, name string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	var query = bson.D{{Key: "id", Value: applicationID}}
@@ -91,25 +126,120 @@ func (d *Dao) DeleteJobApplication(applicationID, name string) error {
 	return nil
 }
 
+// DeleteJobApplications deletes all the job applications matching the query and returns the deleted count
+func (d *Dao) DeleteJobApplications(query bson.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	result, err := database.Operator.DeleteMany(ctx, d.collection, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
 func (d *Dao) UpdateJobApplication(query, update bson.D) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
-	if _, err := database.Operator.Update(ctx, d.collection, query, update); err != nil {
-		return err
+	for attempt := 1; ; attempt++ {
+		sealedQuery, sealedUpdate, guarded, err := d.sealUpdate(ctx, query, update)
+		if err != nil {
+			return err
+		}
+
+		result, err := database.Operator.Update(ctx, d.collection, sealedQuery, sealedUpdate)
+		if err != nil {
+			return err
+		}
+
+		if !guarded || result.MatchedCount > 0 {
+			return nil
+		}
+
+		if attempt == sealAttempts {
+			return errConcurrentUpdate
+		}
 	}
+}
 
-	return nil
+// UpdateJobApplications updates all the job applications matching the query and returns the modified count
+func (d *Dao) UpdateJobApplications(query, update bson.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	// the personal details are encrypted per job application
+	if encryption.Enabled() && setsApplicantDetails(update) {
+		return 0, fmt.Errorf("applicant details can't be updated for many job applications")
+	}
+
+	result, err := database.Operator.UpdateMany(ctx, d.collection, query, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
 }
 
-func (d *Dao) UpdateJobApplicationAndReturnDocument(query, update bson.D) (*JobApplication, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+// UpdateJobApplicationAndReturnDocument updates the first job application matching the query, in the
+// order of the options if any, and returns the updated job application
+func (d *Dao) UpdateJobApplicationAndReturnDocument(query, update bson.D, opts ...*options.FindOneAndUpdateOptions) (*JobApplication, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
-	var jobApplication JobApplication
-	if err := database.Operator.UpdateAndReturnDocument(ctx, d.collection, query, update).Decode(&jobApplication); err != nil {
+	for attempt := 1; ; attempt++ {
+		sealedQuery, sealedUpdate, guarded, err := d.sealUpdate(ctx, query, update)
+		if err != nil {
+			return nil, err
+		}
+
+		var jobApplication JobApplication
+		err = database.Operator.UpdateAndReturnDocument(ctx, d.collection, sealedQuery, sealedUpdate, opts...).Decode(&jobApplication)
+		if guarded && errors.Is(err, mongo.ErrNoDocuments) {
+			if attempt == sealAttempts {
+				return nil, errConcurrentUpdate
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := open(&jobApplication); err != nil {
+			return nil, err
+		}
+
+		return &jobApplication, nil
+	}
+}
+
+// CountJobApplicationsByStatus returns the number of job applications matching the query for each job and status
+func (d *Dao) CountJobApplicationsByStatus(query bson.D) ([]StatusCount, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "job_id", Value: "$job_id"},
+				{Key: "status", Value: "$status"},
+			}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+
+	cursor, err := database.Operator.Aggregate(ctx, d.collection, pipeline)
+	if err != nil {
 		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	return &jobApplication, nil
+	var counts []StatusCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
 }
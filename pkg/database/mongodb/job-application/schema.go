@@ -1,12 +1,26 @@
 package jobapplication
 
-import "time"
+import (
+	"time"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/encryption"
+)
 
 type JobApplication struct {
 	ID               string               `bson:"id" json:"id"`
 	JobID            string               `bson:"job_id" json:"jobId"`
 	ApplicantDetails ApplicantDetails     `bson:"applicant_details" json:"applicantDetails"`
 	Status           JobApplicationStatus `bson:"status" json:"status"`
+	Offer            *Offer               `bson:"offer" json:"offer"`
+	Cancellation     *Cancellation        `bson:"cancellation" json:"cancellation"`
+	Consent          *Consent             `bson:"consent,omitempty" json:"consent,omitempty"`
+	BecknContext     *BecknContext        `bson:"beckn_context" json:"-"`
+	ApplicantKeys    ApplicantKeys        `bson:"applicant_keys" json:"-"`
+	ApplicationKeys  []string             `bson:"application_keys,omitempty" json:"-"` // unique across the applications, see GetApplicationKeys
+	ProfileID        string               `bson:"profile_id,omitempty" json:"profileId,omitempty"`
+	PII              *encryption.Envelope `bson:"pii,omitempty" json:"-"`                              // encrypted personal details of the applicant
+	ErasedAt         *time.Time           `bson:"erased_at,omitempty" json:"erasedAt,omitempty"`       // the personal details were erased on the applicant's request
+	DuplicateOf      string               `bson:"duplicate_of,omitempty" json:"duplicateOf,omitempty"` // earlier application to the job by the same applicant
 	CreatedAt        time.Time            `bson:"created_at" json:"createdAt"`
 	UpdatedAt        time.Time            `bson:"updated_at" json:"updatedAt"`
 }
@@ -21,6 +35,13 @@ type ApplicantDetails struct {
 	Email      string     `bson:"email" json:"email"`
 }
 
+// ApplicantKeys represents the normalized contact details of an applicant, used to match the
+// applications of the same applicant
+type ApplicantKeys struct {
+	Phone string `bson:"phone"`
+	Email string `bson:"email"`
+}
+
 type Documents struct {
 	PANCard        *Document `bson:"pan_card" json:"panCard"`
 	AadharCard     *Document `bson:"aadhar_card" json:"aadharCard"`
@@ -48,5 +69,122 @@ const (
 	JobApplicationStatusOfferRejected        JobApplicationStatus = "OFFER_REJECTED"
 	JobApplicationStatusOfferAccepted        JobApplicationStatus = "OFFER_ACCEPTED"
 	JobApplicationStatusOfferExtended        JobApplicationStatus = "OFFER_EXTENDED"
+	JobApplicationStatusOfferExpired         JobApplicationStatus = "OFFER_EXPIRED"
 	JobApplicationStatusCancelled            JobApplicationStatus = "CANCELLED"
+	JobApplicationStatusJobClosed            JobApplicationStatus = "JOB_CLOSED"
+	JobApplicationStatusWaitlisted           JobApplicationStatus = "WAITLISTED"
+)
+
+// BecknContext represents the beckn context of the confirm request of a job application,
+// it is used to send the unsolicited callbacks to the BAP
+type BecknContext struct {
+	Domain        string `bson:"domain"`
+	Version       string `bson:"version"`
+	BapID         string `bson:"bap_id"`
+	BapURI        string `bson:"bap_uri"`
+	BppID         string `bson:"bpp_id"`
+	BppURI        string `bson:"bpp_uri"`
+	TransactionID string `bson:"transaction_id"`
+	City          string `bson:"city"`
+	Country       string `bson:"country"`
+}
+
+// ActiveStatuses are the statuses of the job applications holding a job vacancy
+var ActiveStatuses = []JobApplicationStatus{
+	JobApplicationStatusApplicationAccepted,
+	JobApplicationStatusAssessmentInProgress,
+	JobApplicationStatusOfferExtended,
+}
+
+// TerminalStatuses are the statuses of the job applications which can't change anymore, neither by the
+// applicants nor by the employers, so they are purged after their retention, except for an offer accepted
+// application which is kept as the record of the hire
+var TerminalStatuses = []JobApplicationStatus{
+	JobApplicationStatusApplicationRejected,
+	JobApplicationStatusOfferRejected,
+	JobApplicationStatusOfferExpired,
+	JobApplicationStatusCancelled,
+	JobApplicationStatusJobClosed,
+}
+
+// CancellableStatuses are the job application statuses in which an applicant can withdraw
+// the application, it can't be withdrawn once an offer is accepted or the application is closed
+var CancellableStatuses = append([]JobApplicationStatus{JobApplicationStatusWaitlisted}, ActiveStatuses...)
+
+// Cancellation represents the details of a cancelled job application
+type Cancellation struct {
+	ReasonID    string      `bson:"reason_id" json:"reasonId"`
+	Reason      string      `bson:"reason" json:"reason"`
+	CancelledBy CancelledBy `bson:"cancelled_by" json:"cancelledBy"`
+	CancelledAt time.Time   `bson:"cancelled_at" json:"cancelledAt"`
+}
+
+// CancelledBy represents who cancelled a job application
+type CancelledBy string
+
+const (
+	CancelledByApplicant CancelledBy = "APPLICANT"
+	CancelledByEmployer  CancelledBy = "EMPLOYER"
 )
+
+// Consent represents the consent of an applicant to share their details with the employer,
+// captured at confirm against the consent terms of the business
+type Consent struct {
+	Version    string     `bson:"version" json:"version"`
+	Scopes     []string   `bson:"scopes" json:"scopes"`
+	GivenAt    time.Time  `bson:"given_at" json:"givenAt"`
+	RecordedAt time.Time  `bson:"recorded_at" json:"recordedAt"`
+	RevokedAt  *time.Time `bson:"revoked_at,omitempty" json:"revokedAt,omitempty"`
+}
+
+// ConsentScopeDocuments is the consent scope to share the documents of an applicant with the employer
+const ConsentScopeDocuments = "DOCUMENTS"
+
+// Allows reports whether the consent is given, and not revoked, for the given scope
+func (c *Consent) Allows(scope string) bool {
+	if c == nil || c.RevokedAt != nil {
+		return false
+	}
+
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Offer represents the job offer extended to an applicant
+type Offer struct {
+	Salary      int         `bson:"salary" json:"salary"`
+	JoiningDate time.Time   `bson:"joining_date" json:"joiningDate"`
+	Location    string      `bson:"location" json:"location"`
+	ExpiresAt   time.Time   `bson:"expires_at" json:"expiresAt"`
+	Status      OfferStatus `bson:"status" json:"status"`
+	Revision    int         `bson:"revision" json:"revision"` // incremented every time the employer revises the offer
+	CreatedAt   time.Time   `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time   `bson:"updated_at" json:"updatedAt"`
+	RespondedAt *time.Time  `bson:"responded_at" json:"respondedAt"`
+}
+
+// OfferStatus represents the status of a job offer
+type OfferStatus string
+
+const (
+	OfferStatusExtended OfferStatus = "EXTENDED"
+	OfferStatusAccepted OfferStatus = "ACCEPTED"
+	OfferStatusRejected OfferStatus = "REJECTED"
+	OfferStatusExpired  OfferStatus = "EXPIRED"
+)
+
+// StatusCount represents the number of job applications of a job in a status
+type StatusCount struct {
+	ID    StatusCountID `bson:"_id"`
+	Count int           `bson:"count"`
+}
+
+type StatusCountID struct {
+	JobID  string               `bson:"job_id"`
+	Status JobApplicationStatus `bson:"status"`
+}
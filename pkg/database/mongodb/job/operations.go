@@ -6,7 +6,6 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
 	"github.com/sirupsen/logrus"
@@ -18,26 +17,38 @@ type DaoInterface interface {
 	ListJobs(query bson.D) ([]Job, error)
 	DeleteJob(jobID string) error
 	UpdateJob(query, update bson.D) error
+	UpdateJobAndReturnDocument(query, update bson.D) (*Job, error)
+	UpdateJobs(query, update bson.D) (int64, error)
+	DeleteJobs(query bson.D) (int64, error)
 }
 
 type Dao struct {
 	collection *mongo.Collection
+	ctx        context.Context
 }
 
 func NewJobDao(collection *mongo.Collection) *Dao {
-	if err := ensure2dsphereIndex(collection, "coordinates_2dsphere_index"); err != nil {
+	if err := database.EnsureIndex(collection, "coordinates_2dsphere_index", bson.D{{Key: "location.coordinates", Value: "2dsphere"}}, nil); err != nil {
 		logrus.Fatalf("Failed to create 2dsphere index for %s collection, %v", collection.Name(), err)
 	}
 	return &Dao{
 		collection: collection,
+		ctx:        context.Background(),
 	}
 }
 
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
 const dbTimeout = 10 * time.Second
 
 // CreateJob creates a job post in the database
 func (d *Dao) CreateJob(job *Job) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	if _, err := database.Operator.Create(ctx, d.collection, job); err != nil {
@@ -49,7 +60,7 @@ func (d *Dao) CreateJob(job *Job) error {
 
 // GetJob returns the job for the given id
 func (d *Dao) GetJob(jobId string) (*Job, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	var query = bson.D{{Key: "id", Value: jobId}}
@@ -69,7 +80,7 @@ func (d *Dao) GetJob(jobId string) (*Job, error) {
 
 // ListJobs lists jobs from the database
 func (d *Dao) ListJobs(query bson.D) ([]Job, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	result, err := database.Operator.List(ctx, d.collection, query)
@@ -85,9 +96,17 @@ func (d *Dao) ListJobs(query bson.D) ([]Job, error) {
 	return jobs, nil
 }
 
+// CountJobs returns the number of jobs matching the query
+func (d *Dao) CountJobs(query bson.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	return database.Operator.Count(ctx, d.collection, query)
+}
+
 // UpdateJob updates a job in the database
 func (d *Dao) UpdateJob(query, update bson.D) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	if _, err := database.Operator.Update(ctx, d.collection, query, update); err != nil {
@@ -97,9 +116,35 @@ func (d *Dao) UpdateJob(query, update bson.D) error {
 	return nil
 }
 
+// UpdateJobAndReturnDocument updates a job in the database and returns the updated job
+func (d *Dao) UpdateJobAndReturnDocument(query, update bson.D) (*Job, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	var job Job
+	if err := database.Operator.UpdateAndReturnDocument(ctx, d.collection, query, update).Decode(&job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// UpdateJobs updates all the jobs matching the query and returns the modified count
+func (d *Dao) UpdateJobs(query, update bson.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	result, err := database.Operator.UpdateMany(ctx, d.collection, query, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
 // DeleteJob deletes a job from the database
 func (d *Dao) DeleteJob(jobID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
 	defer cancel()
 
 	query := bson.D{{Key: "id", Value: jobID}}
@@ -111,37 +156,15 @@ func (d *Dao) DeleteJob(jobID string) error {
 	return nil
 }
 
-func ensure2dsphereIndex(collection *mongo.Collection, indexName string) error {
-	ctx := context.Background()
+// DeleteJobs deletes all the jobs matching the query and returns the deleted count
+func (d *Dao) DeleteJobs(query bson.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
 
-	// Check if the index already exists
-	cursor, err := collection.Indexes().List(ctx)
+	result, err := database.Operator.DeleteMany(ctx, d.collection, query)
 	if err != nil {
-		return err
-	}
-	defer cursor.Close(ctx)
-
-	for cursor.Next(ctx) {
-		var index bson.M
-		if err := cursor.Decode(&index); err != nil {
-			return err
-		}
-		if name, ok := index["name"].(string); ok && name == indexName {
-			// index already exists
-			return nil
-		}
-	}
-
-	// Create the 2dsphere index
-	indexModel := mongo.IndexModel{
-		Keys:    bson.D{{Key: "location.coordinates", Value: "2dsphere"}}, // Index on location.coordinates field
-		Options: options.Index().SetName(indexName),
+		return 0, err
 	}
 
-	if _, err = collection.Indexes().CreateOne(ctx, indexModel); err != nil {
-		return err
-	}
-
-	logrus.Infof("2dsphere index %s created for %s collection", indexName, collection.Name())
-	return nil
+	return result.DeletedCount, nil
 }
@@ -1,6 +1,12 @@
 package job
 
-import "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
+)
 
 // Job represents a job in the database
 type Job struct {
@@ -8,7 +14,11 @@ type Job struct {
 	Name           string            `bson:"name" json:"name"`
 	Description    string            `bson:"description" json:"description"`
 	Type           JobType           `bson:"type" json:"type"`
-	Vacancies      int               `bson:"vacancies" json:"vacancies"`
+	Openings       int               `bson:"openings" json:"openings"`              // number of positions to be filled
+	Hired          int               `bson:"hired" json:"hired"`                    // applications with an accepted offer
+	ApplicationCap int               `bson:"application_cap" json:"applicationCap"` // in-progress applications allowed, 0 for as many as the remaining vacancies
+	Applications   int               `bson:"applications" json:"applications"`      // in-progress applications, see jobapplication.ActiveStatuses
+	Waitlist       bool              `bson:"waitlist" json:"waitlist"`              // applications beyond the application slots are waitlisted
 	SalaryRange    SalaryRange       `bson:"salary_range" json:"salaryRange"`
 	ApplicationIDs []string          `bson:"application_ids" json:"applicationIds"`
 	Business       business.Business `bson:"business" json:"business"`
@@ -16,8 +26,74 @@ type Job struct {
 	WorkDays       WorkDays          `bson:"work_days" json:"workDays"`
 	Eligibility    Eligibility       `bson:"eligibility" json:"eligibility"`
 	Location       Location          `bson:"location" json:"location"`
+	Status         JobStatus         `bson:"status" json:"status"`
+	Closure        *Closure          `bson:"closure" json:"closure"`
+
+	// Vacancies is the remaining vacancies counter of the jobs created before the openings were tracked,
+	// it is migrated to the openings at startup, see job.MigrateLegacyJobs
+	Vacancies int `bson:"vacancies,omitempty" json:"-"`
+}
+
+// RemainingVacancies returns the number of openings not filled yet
+func (j *Job) RemainingVacancies() int {
+	return max(j.Openings-j.Hired, 0)
+}
+
+// AvailableSlots returns the number of new applications the job can take before it is fully booked
+func (j *Job) AvailableSlots() int {
+	remaining := j.RemainingVacancies()
+	if remaining == 0 {
+		return 0
+	}
+
+	limit := j.ApplicationCap
+	if limit == 0 {
+		limit = remaining
+	}
+
+	return max(limit-j.Applications, 0)
+}
+
+// RemainingVacanciesQuery matches the jobs with openings not filled yet
+func RemainingVacanciesQuery() bson.E {
+	return bson.E{Key: "$expr", Value: bson.D{{Key: "$lt", Value: bson.A{"$hired", "$openings"}}}}
 }
 
+// AvailableSlotsQuery matches the jobs which can take a new application, see Job.AvailableSlots
+func AvailableSlotsQuery() bson.E {
+	return bson.E{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$lt", Value: bson.A{"$hired", "$openings"}}},
+		bson.D{{Key: "$lt", Value: bson.A{"$applications", bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{"$application_cap", 0}}},
+			"$application_cap",
+			bson.D{{Key: "$subtract", Value: bson.A{"$openings", "$hired"}}},
+		}}}}}},
+	}}}}
+}
+
+// JobStatus represents the status of a job, jobs without a status are open
+type JobStatus string
+
+const (
+	JobStatusOpen   JobStatus = "OPEN"
+	JobStatusClosed JobStatus = "CLOSED"
+)
+
+// Closure represents the details of a job closed by the employer
+type Closure struct {
+	Reason      ClosureReason `bson:"reason" json:"reason"`
+	Description string        `bson:"description" json:"description"`
+	ClosedAt    time.Time     `bson:"closed_at" json:"closedAt"`
+}
+
+// ClosureReason represents why a job was closed by the employer
+type ClosureReason string
+
+const (
+	ClosureReasonFilled    ClosureReason = "FILLED"
+	ClosureReasonWithdrawn ClosureReason = "WITHDRAWN"
+)
+
 // SalaryRange represents the salary range of a job
 type SalaryRange struct {
 	Min int `bson:"min" json:"min"`
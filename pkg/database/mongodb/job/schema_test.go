@@ -0,0 +1,174 @@
+package job
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAvailableSlots(t *testing.T) {
+	tests := []struct {
+		name          string
+		job           Job
+		wantAvailable int
+		wantRemaining int
+	}{
+		{
+			name:          "no cap, as many slots as the remaining vacancies",
+			job:           Job{Openings: 3, Hired: 1, Applications: 1},
+			wantAvailable: 1,
+			wantRemaining: 2,
+		},
+		{
+			name:          "no cap, every remaining vacancy applied for",
+			job:           Job{Openings: 3, Hired: 1, Applications: 2},
+			wantAvailable: 0,
+			wantRemaining: 2,
+		},
+		{
+			name:          "cap above the remaining vacancies",
+			job:           Job{Openings: 2, ApplicationCap: 5, Applications: 4},
+			wantAvailable: 1,
+			wantRemaining: 2,
+		},
+		{
+			name:          "cap reached",
+			job:           Job{Openings: 2, ApplicationCap: 5, Applications: 5},
+			wantAvailable: 0,
+			wantRemaining: 2,
+		},
+		{
+			name:          "applications beyond the cap",
+			job:           Job{Openings: 2, ApplicationCap: 2, Applications: 3},
+			wantAvailable: 0,
+			wantRemaining: 2,
+		},
+		{
+			name:          "every opening filled",
+			job:           Job{Openings: 2, Hired: 2, ApplicationCap: 5},
+			wantAvailable: 0,
+			wantRemaining: 0,
+		},
+		{
+			name:          "hired beyond the openings",
+			job:           Job{Openings: 1, Hired: 2},
+			wantAvailable: 0,
+			wantRemaining: 0,
+		},
+		{
+			name:          "no openings",
+			job:           Job{},
+			wantAvailable: 0,
+			wantRemaining: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.AvailableSlots(); got != tt.wantAvailable {
+				t.Fatalf("AvailableSlots() = %d, want %d", got, tt.wantAvailable)
+			}
+
+			if got := tt.job.RemainingVacancies(); got != tt.wantRemaining {
+				t.Fatalf("RemainingVacancies() = %d, want %d", got, tt.wantRemaining)
+			}
+
+			document := getDocument(t, &tt.job)
+
+			if got := evaluate(t, document, AvailableSlotsQuery().Value); got != (tt.wantAvailable > 0) {
+				t.Fatalf("AvailableSlotsQuery() matched = %v, want %v", got, tt.wantAvailable > 0)
+			}
+
+			if got := evaluate(t, document, RemainingVacanciesQuery().Value); got != (tt.wantRemaining > 0) {
+				t.Fatalf("RemainingVacanciesQuery() matched = %v, want %v", got, tt.wantRemaining > 0)
+			}
+		})
+	}
+}
+
+// getDocument returns a job as it is stored in the database
+func getDocument(t *testing.T, job *Job) bson.M {
+	t.Helper()
+
+	data, err := bson.Marshal(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document bson.M
+	if err := bson.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	return document
+}
+
+// evaluate evaluates the aggregation expressions of the job queries against a document, the field paths
+// are resolved to their values
+func evaluate(t *testing.T, document bson.M, expression interface{}) interface{} {
+	t.Helper()
+
+	switch expression := expression.(type) {
+	case string:
+		if len(expression) > 1 && expression[0] == '$' {
+			return getNumber(t, document[expression[1:]])
+		}
+		return expression
+	case int, int32, int64:
+		return getNumber(t, expression)
+	case bson.D:
+		if len(expression) != 1 {
+			t.Fatalf("unsupported expression %v", expression)
+		}
+
+		args, ok := expression[0].Value.(bson.A)
+		if !ok {
+			t.Fatalf("unsupported arguments of %s, %v", expression[0].Key, expression[0].Value)
+		}
+
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = evaluate(t, document, arg)
+		}
+
+		switch expression[0].Key {
+		case "$and":
+			for _, value := range values {
+				if value != true {
+					return false
+				}
+			}
+			return true
+		case "$lt":
+			return values[0].(int64) < values[1].(int64)
+		case "$gt":
+			return values[0].(int64) > values[1].(int64)
+		case "$subtract":
+			return values[0].(int64) - values[1].(int64)
+		case "$cond":
+			if values[0] == true {
+				return values[1]
+			}
+			return values[2]
+		}
+	}
+
+	t.Fatalf("unsupported expression %v", expression)
+	return nil
+}
+
+func getNumber(t *testing.T, value interface{}) int64 {
+	t.Helper()
+
+	switch value := value.(type) {
+	case int:
+		return int64(value)
+	case int32:
+		return int64(value)
+	case int64:
+		return value
+	}
+
+	t.Fatalf("unsupported number %T", value)
+	return 0
+}
@@ -0,0 +1,96 @@
+package messagearchive
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+)
+
+type DaoInterface interface {
+	CreateMessage(message *Message) error
+	ListMessages(query bson.D) ([]Message, error)
+	DeleteMessages(query bson.D) (int64, error)
+}
+
+type Dao struct {
+	collection *mongo.Collection
+	ctx        context.Context
+}
+
+const dbTimeout = 10 * time.Second
+
+// NewMessageArchiveDao returns the dao of the archived messages, the messages expire after the given
+// retention, a zero retention keeps them forever
+func NewMessageArchiveDao(collection *mongo.Collection, retention time.Duration) *Dao {
+	// the messages are listed per transaction
+	if err := database.EnsureIndex(collection, "transaction_created_at_index",
+		bson.D{{Key: "transaction_id", Value: 1}, {Key: "created_at", Value: 1}}, nil); err != nil {
+		logrus.Fatalf("Failed to create transaction index for %s collection, %v", collection.Name(), err)
+	}
+	if err := database.EnsureTTLIndex(collection, "created_at_ttl_index", int32(retention.Seconds())); err != nil {
+		logrus.Fatalf("Failed to create TTL index for %s collection, %v", collection.Name(), err)
+	}
+	return &Dao{
+		collection: collection,
+		ctx:        context.Background(),
+	}
+}
+
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
+// CreateMessage archives a message
+func (d *Dao) CreateMessage(message *Message) error {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	if _, err := database.Operator.Create(ctx, d.collection, message); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListMessages lists the archived messages matching the query, in the order they were exchanged
+func (d *Dao) ListMessages(query bson.D) ([]Message, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := database.Operator.List(ctx, d.collection, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// DeleteMessages deletes all the archived messages matching the query and returns the deleted count
+func (d *Dao) DeleteMessages(query bson.D) (int64, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	result, err := database.Operator.DeleteMany(ctx, d.collection, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,31 @@
+package messagearchive
+
+import "time"
+
+// Message represents a beckn message received from or sent to a BAP, archived as exchanged
+type Message struct {
+	ID            string              `bson:"id" json:"id"`
+	TransactionID string              `bson:"transaction_id" json:"transactionId"`
+	MessageID     string              `bson:"message_id" json:"messageId"`
+	Action        string              `bson:"action" json:"action"`
+	Direction     Direction           `bson:"direction" json:"direction"`
+	BapID         string              `bson:"bap_id" json:"bapId"`
+	Method        string              `bson:"method" json:"method"`
+	URL           string              `bson:"url" json:"url"`
+	Headers       map[string][]string `bson:"headers" json:"headers"`
+	Body          string              `bson:"body" json:"body"`
+	StatusCode    int                 `bson:"status_code" json:"statusCode"`
+	ResponseBody  string              `bson:"response_body" json:"responseBody"`
+	Ack           string              `bson:"ack" json:"ack"`                         // ACK or NACK, empty when the response isn't a beckn ack
+	Error         string              `bson:"error,omitempty" json:"error,omitempty"` // error of the NACK, or of the failed callback
+	LatencyMillis int64               `bson:"latency_millis" json:"latencyMillis"`
+	CreatedAt     time.Time           `bson:"created_at" json:"createdAt"`
+}
+
+// Direction represents whether a message was received by the adapter or sent by it
+type Direction string
+
+const (
+	DirectionInbound  Direction = "INBOUND"
+	DirectionOutbound Direction = "OUTBOUND"
+)
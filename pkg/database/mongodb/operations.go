@@ -2,23 +2,37 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/log"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/metrics"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/tracing"
 )
 
 type MongoOperator interface {
 	Create(ctx context.Context, collection *mongo.Collection, document interface{}) (*mongo.InsertOneResult, error)
 	Get(ctx context.Context, collection *mongo.Collection, query bson.D) *mongo.SingleResult
-	List(ctx context.Context, collection *mongo.Collection, query bson.D) (*mongo.Cursor, error)
+	List(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.FindOptions) (*mongo.Cursor, error)
 	Update(ctx context.Context, collection *mongo.Collection, query, update bson.D,
 		opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	UpdateMany(ctx context.Context, collection *mongo.Collection, query, update bson.D,
 		opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
-	UpdateAndReturnDocument(ctx context.Context, collection *mongo.Collection, query, update bson.D) *mongo.SingleResult
+	UpdateAndReturnDocument(ctx context.Context, collection *mongo.Collection, query, update bson.D,
+		opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult
 	Delete(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	DeleteMany(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
 	Aggregate(ctx context.Context, collection *mongo.Collection, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
+	Count(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.CountOptions) (int64, error)
 	ListDataBase(ctx context.Context, mclient *mongo.Client) ([]string, error)
 }
 
@@ -31,47 +45,83 @@ var (
 
 // Create puts a document in the database
 func (m *MongoOperations) Create(ctx context.Context, collection *mongo.Collection, document interface{}) (*mongo.InsertOneResult, error) {
-	return collection.InsertOne(ctx, document)
+	ctx, end := startOperation(ctx, "create", collection)
+	result, err := collection.InsertOne(ctx, document)
+	return result, end(err)
 }
 
 // Get fetches a document from the database based on a query
 func (m *MongoOperations) Get(ctx context.Context, collection *mongo.Collection, query bson.D) *mongo.SingleResult {
-	return collection.FindOne(ctx, query)
+	ctx, end := startOperation(ctx, "get", collection)
+	result := collection.FindOne(ctx, query)
+	if err := end(getSingleResultError(result)); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return result
 }
 
 // List fetches a list of documents from the database based on a query
-func (m *MongoOperations) List(ctx context.Context, collection *mongo.Collection, query bson.D) (*mongo.Cursor, error) {
-	return collection.Find(ctx, query)
+func (m *MongoOperations) List(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	ctx, end := startOperation(ctx, "list", collection)
+	cursor, err := collection.Find(ctx, query, opts...)
+	return cursor, end(err)
 }
 
 // Update updates a document in the database based on a query
 func (m *MongoOperations) Update(ctx context.Context, collection *mongo.Collection, query, update bson.D, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	return collection.UpdateOne(ctx, query, update, opts...)
+	ctx, end := startOperation(ctx, "update", collection)
+	result, err := collection.UpdateOne(ctx, query, update, opts...)
+	return result, end(err)
 }
 
 // UpdateAndReturnDocument updates a document and then returns the updated document
-func (m *MongoOperations) UpdateAndReturnDocument(ctx context.Context, collection *mongo.Collection, query, update bson.D) *mongo.SingleResult {
-	return collection.FindOneAndUpdate(ctx, query, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+func (m *MongoOperations) UpdateAndReturnDocument(ctx context.Context, collection *mongo.Collection, query, update bson.D, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	opts = append([]*options.FindOneAndUpdateOptions{options.FindOneAndUpdate().SetReturnDocument(options.After)}, opts...)
+	ctx, end := startOperation(ctx, "update_and_return_document", collection)
+	result := collection.FindOneAndUpdate(ctx, query, update, opts...)
+	if err := end(getSingleResultError(result)); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return result
 }
 
 // Update updates a document in the database based on a query
 func (m *MongoOperations) UpdateMany(ctx context.Context, collection *mongo.Collection, query, update bson.D, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	return collection.UpdateMany(ctx, query, update, opts...)
+	ctx, end := startOperation(ctx, "update_many", collection)
+	result, err := collection.UpdateMany(ctx, query, update, opts...)
+	return result, end(err)
 }
 
 // Delete removes a document from the database based on a query
 func (m *MongoOperations) Delete(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
-	return collection.DeleteOne(ctx, query, opts...)
+	ctx, end := startOperation(ctx, "delete", collection)
+	result, err := collection.DeleteOne(ctx, query, opts...)
+	return result, end(err)
+}
+
+// DeleteMany removes all the documents matching a query from the database
+func (m *MongoOperations) DeleteMany(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	ctx, end := startOperation(ctx, "delete_many", collection)
+	result, err := collection.DeleteMany(ctx, query, opts...)
+	return result, end(err)
 }
 
 func (m *MongoOperations) Aggregate(ctx context.Context, collection *mongo.Collection, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	ctx, end := startOperation(ctx, "aggregate", collection)
 	result, err := collection.Aggregate(ctx, pipeline, opts...)
-	if err != nil {
+	if err := end(err); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
+// Count returns the number of documents matching a query
+func (m *MongoOperations) Count(ctx context.Context, collection *mongo.Collection, query bson.D, opts ...*options.CountOptions) (int64, error) {
+	ctx, end := startOperation(ctx, "count", collection)
+	count, err := collection.CountDocuments(ctx, query, opts...)
+	return count, end(err)
+}
+
 func (m *MongoOperations) ListDataBase(ctx context.Context, mclient *mongo.Client) ([]string, error) {
 	dbs, err := mclient.ListDatabaseNames(ctx, bson.D{})
 	if err != nil {
@@ -80,3 +130,101 @@ func (m *MongoOperations) ListDataBase(ctx context.Context, mclient *mongo.Clien
 
 	return dbs, nil
 }
+
+// startOperation starts the span of a database operation, and returns its end which records the operation
+// and returns its error with the message of a server error left out, see sanitizeError
+func startOperation(ctx context.Context, operation string, collection *mongo.Collection) (context.Context, func(err error) error) {
+	start := time.Now()
+
+	ctx, span := tracing.Start(ctx, "mongodb "+operation+" "+collection.Name(), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.operation.name", operation),
+		attribute.String("db.collection.name", collection.Name()),
+	))
+
+	return ctx, func(err error) error {
+		err = sanitizeError(err)
+
+		latency := time.Since(start)
+		if err != nil {
+			log.WithContext(ctx).Warnf("Failed mongodb %s on %s collection after %s, %v", operation, collection.Name(), latency, err)
+		} else {
+			log.WithContext(ctx).Debugf("Completed mongodb %s on %s collection in %s", operation, collection.Name(), latency)
+		}
+
+		metrics.ObserveMongoOperation(operation, collection.Name(), start, err)
+		tracing.End(span, err)
+
+		return err
+	}
+}
+
+// getSingleResultError returns the error of a single document operation, not finding the document isn't
+// an error of the operation
+func getSingleResultError(result *mongo.SingleResult) error {
+	if err := result.Err(); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	return nil
+}
+
+// serverError is a server error of an operation whose message is left out, it may quote the values of the
+// documents, for eg. the duplicate key of an E11000 error, the server error is still matched through Unwrap
+type serverError struct {
+	err   error
+	codes []int
+}
+
+func (e *serverError) Error() string {
+	if mongo.IsDuplicateKeyError(e.err) {
+		return "mongodb duplicate key error"
+	}
+
+	codes := make([]string, len(e.codes))
+	for i, code := range e.codes {
+		codes[i] = strconv.Itoa(code)
+	}
+
+	return "mongodb server error, codes " + strings.Join(codes, ", ")
+}
+
+func (e *serverError) Unwrap() error {
+	return e.err
+}
+
+// sanitizeError returns the error of an operation, with the message of a server error replaced by its codes
+// so it can be logged and returned to the callers
+func sanitizeError(err error) error {
+	var (
+		writeException     mongo.WriteException
+		bulkWriteException mongo.BulkWriteException
+		commandError       mongo.CommandError
+		sanitized          = &serverError{err: err}
+	)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &writeException):
+		for _, writeError := range writeException.WriteErrors {
+			sanitized.codes = append(sanitized.codes, writeError.Code)
+		}
+		if writeException.WriteConcernError != nil {
+			sanitized.codes = append(sanitized.codes, writeException.WriteConcernError.Code)
+		}
+	case errors.As(err, &bulkWriteException):
+		for _, writeError := range bulkWriteException.WriteErrors {
+			sanitized.codes = append(sanitized.codes, writeError.Code)
+		}
+		if bulkWriteException.WriteConcernError != nil {
+			sanitized.codes = append(sanitized.codes, bulkWriteException.WriteConcernError.Code)
+		}
+	case errors.As(err, &commandError):
+		sanitized.codes = append(sanitized.codes, int(commandError.Code))
+	default:
+		return err
+	}
+
+	return sanitized
+}
@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestSanitizeError(t *testing.T) {
+	const value = "job-1/phone:9876543210"
+
+	duplicateKey := mongo.WriteException{WriteErrors: []mongo.WriteError{{
+		Code:    11000,
+		Message: `E11000 duplicate key error collection: job-applications index: application_keys_unique_index dup key: { application_keys: "` + value + `" }`,
+	}}}
+
+	tests := []struct {
+		name          string
+		err           error
+		want          string
+		wantDuplicate bool
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name: "client error",
+			err:  mongo.ErrNoDocuments,
+			want: mongo.ErrNoDocuments.Error(),
+		},
+		{
+			name:          "duplicate key",
+			err:           duplicateKey,
+			want:          "mongodb duplicate key error",
+			wantDuplicate: true,
+		},
+		{
+			name: "write concern error",
+			err: mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{
+				Code:    64,
+				Message: "waiting for replication timed out",
+			}},
+			want: "mongodb server error, codes 64",
+		},
+		{
+			name: "command error",
+			err: mongo.CommandError{
+				Code:    11000,
+				Name:    "DuplicateKey",
+				Message: `E11000 duplicate key error dup key: { application_keys: "` + value + `" }`,
+			},
+			want:          "mongodb duplicate key error",
+			wantDuplicate: true,
+		},
+		{
+			name: "bulk write error",
+			err: mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{
+				{WriteError: mongo.WriteError{Code: 121, Message: "Document failed validation: " + value}},
+				{WriteError: mongo.WriteError{Code: 2, Message: "bad value " + value}},
+			}},
+			want: "mongodb server error, codes 121, 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sanitizeError(tt.err)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("sanitizeError() = %v, want nil", err)
+				}
+				return
+			}
+
+			if err.Error() != tt.want {
+				t.Fatalf("sanitizeError() = %q, want %q", err.Error(), tt.want)
+			}
+			if strings.Contains(err.Error(), value) {
+				t.Fatalf("sanitizeError() = %q quotes the document value", err.Error())
+			}
+			if mongo.IsDuplicateKeyError(err) != tt.wantDuplicate {
+				t.Fatalf("IsDuplicateKeyError() = %v, want %v", !tt.wantDuplicate, tt.wantDuplicate)
+			}
+			if !errors.Is(err, tt.err) && !errors.As(err, new(mongo.ServerError)) {
+				t.Fatal("sanitizeError() doesn't wrap the error")
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package rating
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	database "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+)
+
+type DaoInterface interface {
+	CreateRating(rating *Rating) error
+	ListRatings(query bson.D) ([]Rating, error)
+	AggregateRatings(category Category, groupBy string, query bson.D) ([]Aggregate, error)
+	GetRatingDistribution(query bson.D) ([]Distribution, error)
+}
+
+type Dao struct {
+	collection *mongo.Collection
+	ctx        context.Context
+}
+
+const dbTimeout = 10 * time.Second
+
+func NewRatingDao(collection *mongo.Collection) *Dao {
+	if err := database.EnsureIndex(collection, "job_application_category_unique_index",
+		bson.D{{Key: "job_application_id", Value: 1}, {Key: "category", Value: 1}}, options.Index().SetUnique(true)); err != nil {
+		logrus.Fatalf("Failed to create unique index for %s collection, %v", collection.Name(), err)
+	}
+	return &Dao{
+		collection: collection,
+		ctx:        context.Background(),
+	}
+}
+
+// WithContext returns a copy of the dao whose operations carry the given context, for eg. a trace
+func (d *Dao) WithContext(ctx context.Context) *Dao {
+	dao := *d
+	dao.ctx = ctx
+	return &dao
+}
+
+// CreateRating stores a rating, only one rating per category is allowed for a job application
+func (d *Dao) CreateRating(rating *Rating) error {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	if _, err := database.Operator.Create(ctx, d.collection, rating); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListRatings lists ratings from the database
+func (d *Dao) ListRatings(query bson.D) ([]Rating, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	cursor, err := database.Operator.List(ctx, d.collection, query)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ratings []Rating
+	if err := cursor.All(ctx, &ratings); err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// AggregateRatings returns the average rating and the rating count of the given category,
+// grouped by the given field, for eg. 'business_id' or 'job_id'
+func (d *Dao) AggregateRatings(category Category, groupBy string, query bson.D) ([]Aggregate, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: append(bson.D{{Key: "category", Value: category}}, query...)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + groupBy},
+			{Key: "average", Value: bson.D{{Key: "$avg", Value: "$value"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+
+	cursor, err := database.Operator.Aggregate(ctx, d.collection, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var aggregates []Aggregate
+	if err := cursor.All(ctx, &aggregates); err != nil {
+		return nil, err
+	}
+
+	return aggregates, nil
+}
+
+// GetRatingDistribution returns the number of ratings given for each rating value
+func (d *Dao) GetRatingDistribution(query bson.D) ([]Distribution, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, dbTimeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$value"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := database.Operator.Aggregate(ctx, d.collection, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var distribution []Distribution
+	if err := cursor.All(ctx, &distribution); err != nil {
+		return nil, err
+	}
+
+	return distribution, nil
+}
@@ -0,0 +1,43 @@
+package rating
+
+import "time"
+
+// Rating represents a rating given by an applicant for a job application
+type Rating struct {
+	ID               string    `bson:"id" json:"id"`
+	JobApplicationID string    `bson:"job_application_id" json:"jobApplicationId"`
+	JobID            string    `bson:"job_id" json:"jobId"`
+	BusinessID       string    `bson:"business_id" json:"businessId"`
+	Category         Category  `bson:"category" json:"category"`
+	Value            int       `bson:"value" json:"value"`
+	BapID            string    `bson:"bap_id" json:"bapId"`
+	CreatedAt        time.Time `bson:"created_at" json:"createdAt"`
+}
+
+// Category represents the entity being rated
+type Category string
+
+const (
+	// CategoryProvider rates the business, i.e. the employer
+	CategoryProvider Category = "PROVIDER"
+	// CategoryItem rates the job
+	CategoryItem Category = "ITEM"
+)
+
+// Aggregate represents the aggregated ratings of a business or a job
+type Aggregate struct {
+	ID      string  `bson:"_id" json:"id"`
+	Average float64 `bson:"average" json:"average"`
+	Count   int     `bson:"count" json:"count"`
+}
+
+// Distribution represents the number of ratings given for a rating value
+type Distribution struct {
+	Value int `bson:"_id" json:"value"`
+	Count int `bson:"count" json:"count"`
+}
+
+const (
+	MinValue = 1
+	MaxValue = 5
+)
@@ -0,0 +1,246 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// keySize is the size of the key encryption keys, the data keys and the blind index key, for AES-256
+const keySize = 32
+
+// Envelope holds encrypted data along with its data key, the data key itself is encrypted
+// with the key encryption key identified by the key ID
+type Envelope struct {
+	KeyID      string `bson:"key_id"`
+	DataKey    []byte `bson:"data_key"`
+	Ciphertext []byte `bson:"ciphertext"`
+}
+
+// KeyFile represents the local key file standing in for a key management service, the keys are base64
+// encoded, a key is rotated by adding a new key, making it active and running the rotate-keys command
+//
+//	{"activeKey": "k2", "keys": {"k1": "...", "k2": "..."}, "indexKey": "..."}
+type KeyFile struct {
+	ActiveKey string            `json:"activeKey"`
+	Keys      map[string]string `json:"keys"`
+	IndexKey  string            `json:"indexKey"` // never rotated, the blind indexes are computed with it
+}
+
+type keyring struct {
+	activeKey string
+	keys      map[string][]byte
+	indexKey  []byte
+}
+
+// ring is the loaded keyring, encryption is disabled without a key file
+var ring *keyring
+
+// Init loads the keys from the key file, encryption stays disabled when no key file is provided
+func Init(keyFile string) error {
+	if keyFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read key file, %v", err)
+	}
+
+	var file KeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse key file, %v", err)
+	}
+
+	loaded := &keyring{
+		activeKey: file.ActiveKey,
+		keys:      make(map[string][]byte),
+	}
+
+	for id, encoded := range file.Keys {
+		if loaded.keys[id], err = decodeKey(encoded); err != nil {
+			return fmt.Errorf("invalid key %s, %v", id, err)
+		}
+	}
+
+	if _, ok := loaded.keys[file.ActiveKey]; !ok {
+		return fmt.Errorf("active key %s not found in the key file", file.ActiveKey)
+	}
+
+	if loaded.indexKey, err = decodeKey(file.IndexKey); err != nil {
+		return fmt.Errorf("invalid index key, %v", err)
+	}
+
+	ring = loaded
+	return nil
+}
+
+// Enabled reports whether a key file is loaded
+func Enabled() bool {
+	return ring != nil
+}
+
+// Check verifies the loaded keys by sealing and opening a probe with the active key
+func Check() error {
+	if ring == nil {
+		return errors.New("no key file loaded")
+	}
+
+	probe := []byte("probe")
+
+	envelope, err := Seal(probe)
+	if err != nil {
+		return fmt.Errorf("failed to seal probe, %v", err)
+	}
+
+	opened, err := Open(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to open probe, %v", err)
+	}
+
+	if !bytes.Equal(opened, probe) {
+		return errors.New("opened probe doesn't match")
+	}
+
+	return nil
+}
+
+// ActiveKeyID returns the ID of the key encryption key used for the new envelopes
+func ActiveKeyID() string {
+	if ring == nil {
+		return ""
+	}
+	return ring.activeKey
+}
+
+// Seal encrypts the data with a new data key, and encrypts the data key with the active key
+func Seal(plaintext []byte) (*Envelope, error) {
+	if ring == nil {
+		return nil, errors.New("encryption is not enabled")
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := encrypt(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := encrypt(ring.keys[ring.activeKey], dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		KeyID:      ring.activeKey,
+		DataKey:    wrappedKey,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Open decrypts the data of an envelope
+func Open(envelope *Envelope) ([]byte, error) {
+	if ring == nil {
+		return nil, errors.New("encryption is not enabled")
+	}
+
+	key, ok := ring.keys[envelope.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found", envelope.KeyID)
+	}
+
+	dataKey, err := decrypt(key, envelope.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key, %v", err)
+	}
+
+	return decrypt(dataKey, envelope.Ciphertext)
+}
+
+// BlindIndex returns a keyed hash of the value, so encrypted values can still be matched for equality,
+// the value is returned as is when encryption is disabled
+func BlindIndex(value string) string {
+	if ring == nil || value == "" {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, ring.indexKey)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateKey returns a new random key, base64 encoded for the key file
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	return key, nil
+}
+
+// encrypt encrypts with AES-GCM, the nonce is prepended to the ciphertext
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decrypt(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
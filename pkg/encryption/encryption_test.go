@@ -0,0 +1,222 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeKeyFile(t *testing.T, file KeyFile) string {
+	t.Helper()
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestInit(t *testing.T) {
+	var (
+		key      = newKey(t)
+		indexKey = newKey(t)
+		shortKey = base64.StdEncoding.EncodeToString([]byte("short"))
+	)
+
+	tests := []struct {
+		name    string
+		file    KeyFile
+		wantErr string
+	}{
+		{
+			name: "valid key file",
+			file: KeyFile{ActiveKey: "k1", Keys: map[string]string{"k1": key}, IndexKey: indexKey},
+		},
+		{
+			name:    "active key not found",
+			file:    KeyFile{ActiveKey: "k2", Keys: map[string]string{"k1": key}, IndexKey: indexKey},
+			wantErr: "active key k2 not found",
+		},
+		{
+			name:    "key of the wrong size",
+			file:    KeyFile{ActiveKey: "k1", Keys: map[string]string{"k1": shortKey}, IndexKey: indexKey},
+			wantErr: "invalid key k1",
+		},
+		{
+			name:    "key not base64 encoded",
+			file:    KeyFile{ActiveKey: "k1", Keys: map[string]string{"k1": "not base64!"}, IndexKey: indexKey},
+			wantErr: "invalid key k1",
+		},
+		{
+			name:    "missing index key",
+			file:    KeyFile{ActiveKey: "k1", Keys: map[string]string{"k1": key}},
+			wantErr: "invalid index key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { ring = nil })
+
+			err := Init(writeKeyFile(t, tt.file))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Init() error = %v", err)
+				}
+				if err := Check(); err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Init() error = %v, want %q", err, tt.wantErr)
+			}
+			if Enabled() {
+				t.Fatal("Enabled() = true after a failed Init()")
+			}
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	var (
+		oldKey   = newKey(t)
+		indexKey = newKey(t)
+	)
+
+	if err := Init(writeKeyFile(t, KeyFile{ActiveKey: "k1", Keys: map[string]string{"k1": oldKey}, IndexKey: indexKey})); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ring = nil })
+
+	rotated, err := Seal([]byte("sealed with the rotated key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Init(writeKeyFile(t, KeyFile{ActiveKey: "k2", Keys: map[string]string{"k1": oldKey, "k2": newKey(t)}, IndexKey: indexKey})); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		envelope func(t *testing.T) *Envelope
+		want     []byte
+		wantErr  bool
+	}{
+		{
+			name: "sealed with the active key",
+			envelope: func(t *testing.T) *Envelope {
+				envelope, err := Seal([]byte("personal details"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if envelope.KeyID != "k2" {
+					t.Fatalf("KeyID = %s, want k2", envelope.KeyID)
+				}
+				return envelope
+			},
+			want: []byte("personal details"),
+		},
+		{
+			name:     "sealed with a rotated key",
+			envelope: func(*testing.T) *Envelope { return rotated },
+			want:     []byte("sealed with the rotated key"),
+		},
+		{
+			name: "unknown key",
+			envelope: func(*testing.T) *Envelope {
+				envelope := *rotated
+				envelope.KeyID = "k3"
+				return &envelope
+			},
+			wantErr: true,
+		},
+		{
+			name: "tampered ciphertext",
+			envelope: func(*testing.T) *Envelope {
+				envelope := *rotated
+				envelope.Ciphertext = bytes.Clone(rotated.Ciphertext)
+				envelope.Ciphertext[len(envelope.Ciphertext)-1] ^= 1
+				return &envelope
+			},
+			wantErr: true,
+		},
+		{
+			name: "truncated ciphertext",
+			envelope: func(*testing.T) *Envelope {
+				envelope := *rotated
+				envelope.Ciphertext = rotated.Ciphertext[:4]
+				return &envelope
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Open(tt.envelope(t))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("Open() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	if got := BlindIndex("9876543210"); got != "9876543210" {
+		t.Fatalf("BlindIndex() = %s without encryption, want the value as is", got)
+	}
+
+	if err := Init(writeKeyFile(t, KeyFile{ActiveKey: "k1", Keys: map[string]string{"k1": newKey(t)}, IndexKey: newKey(t)})); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ring = nil })
+
+	tests := []struct {
+		name      string
+		a, b      string
+		wantEqual bool
+	}{
+		{name: "same value", a: "9876543210", b: "9876543210", wantEqual: true},
+		{name: "different values", a: "9876543210", b: "9876543211", wantEqual: false},
+		{name: "empty value", a: "", b: "", wantEqual: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := BlindIndex(tt.a), BlindIndex(tt.b)
+			if (a == b) != tt.wantEqual {
+				t.Fatalf("BlindIndex(%q) = %s, BlindIndex(%q) = %s, want equal %v", tt.a, a, tt.b, b, tt.wantEqual)
+			}
+			if tt.a != "" && a == tt.a {
+				t.Fatalf("BlindIndex(%q) returned the value as is", tt.a)
+			}
+		})
+	}
+}
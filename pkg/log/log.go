@@ -1,11 +1,18 @@
 package log
 
 import (
+	"context"
+	"fmt"
 	"os"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/tracing"
 )
 
+type requestIDKey struct{}
+
 func InitLogger() {
 	// Log as JSON instead of the default ASCII formatter.
 	logrus.SetFormatter(&logrus.JSONFormatter{})
@@ -14,3 +21,68 @@ func InitLogger() {
 	// Can be any io.Writer, see below for File example
 	logrus.SetOutput(os.Stdout)
 }
+
+// Configure sets the log level, for eg. debug or info, and the log format, json or text
+func Configure(level, format string) error {
+	logLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logrus.SetLevel(logLevel)
+
+	switch format {
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	case "text":
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		return fmt.Errorf("unknown log format %s", format)
+	}
+
+	return nil
+}
+
+// WithRequestID returns a context of the request with the given id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// GetRequestID returns the id of the request of the context, if any
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithContext returns a logger carrying the request id, the beckn message and the trace of the context,
+// so the logs of concurrent requests and their background processing can be told apart
+func WithContext(ctx context.Context) *logrus.Entry {
+	return logrus.WithFields(Fields(ctx))
+}
+
+// Fields returns the log fields of the context
+func Fields(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{}
+
+	if requestID := GetRequestID(ctx); requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	if message := tracing.GetMessage(ctx); message != nil {
+		for key, value := range map[string]string{
+			"action":         message.Action,
+			"transaction_id": message.TransactionID,
+			"message_id":     message.MessageID,
+			"bap_id":         message.BapID,
+		} {
+			if value != "" {
+				fields[key] = value
+			}
+		}
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields["trace_id"] = spanContext.TraceID().String()
+	}
+
+	return fields
+}
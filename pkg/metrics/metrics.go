@@ -0,0 +1,238 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	apiclient "github.com/ONEST-Network/Job-Manager-Adapter/pkg/api-client"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+)
+
+const namespace = "adapter"
+
+var (
+	becknRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "beckn_requests_total",
+		Help:      "Number of beckn requests received, by action and ACK or NACK.",
+	}, []string{"action", "ack"})
+
+	becknRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "beckn_request_duration_seconds",
+		Help:      "Time taken to ack the beckn requests, by action.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"action"})
+
+	asyncInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "async_in_flight",
+		Help:      "Number of acked beckn requests being processed in the background, by action.",
+	}, []string{"action"})
+
+	asyncQueued = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "async_queued",
+		Help:      "Number of acked beckn requests waiting for a worker, by action.",
+	}, []string{"action"})
+
+	asyncRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "async_rejected_total",
+		Help:      "Number of beckn requests NACKed as the workers of their action were saturated, by action.",
+	}, []string{"action"})
+
+	asyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "async_duration_seconds",
+		Help:      "Time taken to process the acked beckn requests in the background, including the callback, by action.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"action"})
+
+	callbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "callbacks_total",
+		Help:      "Number of callbacks sent to the BAPs, by BAP, action and outcome.",
+	}, []string{"bap_id", "action", "outcome"})
+
+	callbackDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "callback_duration_seconds",
+		Help:      "Time taken by the BAPs to ack the callbacks, by BAP and action.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"bap_id", "action"})
+
+	mongoOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongodb_operation_duration_seconds",
+		Help:      "Time taken by the database operations, by operation and collection.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "collection"})
+
+	mongoOperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mongodb_operation_errors_total",
+		Help:      "Number of failed database operations, by operation and collection.",
+	}, []string{"operation", "collection"})
+
+	openJobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "open_jobs",
+		Help:      "Number of open jobs.",
+	})
+
+	jobApplications = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_applications",
+		Help:      "Number of job applications, by status.",
+	}, []string{"status"})
+
+	retentionPurged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "retention_purged_total",
+		Help:      "Number of documents purged after their retention, by collection.",
+	}, []string{"collection"})
+)
+
+// asyncBacklog is the number of acked beckn requests queued or being processed in the background, of all actions
+var asyncBacklog atomic.Int64
+
+func init() {
+	prometheus.MustRegister(
+		becknRequests,
+		becknRequestDuration,
+		asyncInFlight,
+		asyncQueued,
+		asyncRejected,
+		asyncDuration,
+		callbacks,
+		callbackDuration,
+		mongoOperationDuration,
+		mongoOperationErrors,
+		openJobs,
+		jobApplications,
+		retentionPurged,
+	)
+}
+
+// Handler serves the metrics in the prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveBecknRequest records a beckn request along with whether it was acked
+func ObserveBecknRequest(action string, acked bool, duration time.Duration) {
+	ack := "ACK"
+	if !acked {
+		ack = "NACK"
+	}
+
+	becknRequests.WithLabelValues(action, ack).Inc()
+	becknRequestDuration.WithLabelValues(action).Observe(duration.Seconds())
+}
+
+// TrackAsync runs the background processing of an acked beckn request, recording it while in flight
+func TrackAsync(action string, task func()) {
+	start := time.Now()
+
+	asyncBacklog.Add(1)
+	asyncInFlight.WithLabelValues(action).Inc()
+	defer func() {
+		asyncBacklog.Add(-1)
+		asyncInFlight.WithLabelValues(action).Dec()
+		asyncDuration.WithLabelValues(action).Observe(time.Since(start).Seconds())
+	}()
+
+	task()
+}
+
+// QueueAsync records an acked beckn request waiting for a worker, until the returned dequeue is called
+func QueueAsync(action string) (dequeue func()) {
+	asyncBacklog.Add(1)
+	asyncQueued.WithLabelValues(action).Inc()
+
+	return func() {
+		asyncBacklog.Add(-1)
+		asyncQueued.WithLabelValues(action).Dec()
+	}
+}
+
+// RejectAsync records a beckn request NACKed as the workers of its action were saturated
+func RejectAsync(action string) {
+	asyncRejected.WithLabelValues(action).Inc()
+}
+
+// AsyncBacklog returns the number of acked beckn requests queued or being processed in the background
+func AsyncBacklog() int64 {
+	return asyncBacklog.Load()
+}
+
+// ObserveCallback records a callback sent to a BAP, it is an api client observer
+func ObserveCallback(exchange *apiclient.Exchange) {
+	var request struct {
+		Context struct {
+			Action string `json:"action"`
+			BapID  string `json:"bap_id"`
+		} `json:"context"`
+	}
+
+	// the calls which aren't beckn callbacks are skipped
+	if err := json.Unmarshal(exchange.RequestBody, &request); err != nil || request.Context.Action == "" {
+		return
+	}
+
+	outcome := "success"
+	if exchange.Err != nil || exchange.StatusCode != http.StatusOK {
+		outcome = "failure"
+	}
+
+	bapID := getBapLabel(request.Context.BapID)
+
+	callbacks.WithLabelValues(bapID, request.Context.Action, outcome).Inc()
+	callbackDuration.WithLabelValues(bapID, request.Context.Action).Observe(exchange.Latency.Seconds())
+}
+
+// getBapLabel returns the bap_id label of a BAP, the BAPs not subscribed share the 'other' label so the
+// bap_id of the requests, which isn't authenticated, can't add labels without bound
+func getBapLabel(bapID string) string {
+	if slices.Contains(config.Config.SubscribedBapIds, bapID) {
+		return bapID
+	}
+
+	return "other"
+}
+
+// ObserveMongoOperation records a database operation started at the given time
+func ObserveMongoOperation(operation, collection string, start time.Time, err error) {
+	mongoOperationDuration.WithLabelValues(operation, collection).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		mongoOperationErrors.WithLabelValues(operation, collection).Inc()
+	}
+}
+
+// SetOpenJobs records the number of open jobs
+func SetOpenJobs(count int64) {
+	openJobs.Set(float64(count))
+}
+
+// SetJobApplications records the number of job applications in each status, the statuses missing from
+// the counts are reset
+func SetJobApplications(counts map[string]int) {
+	jobApplications.Reset()
+
+	for status, count := range counts {
+		jobApplications.WithLabelValues(status).Set(float64(count))
+	}
+}
+
+// ObserveRetentionPurge records the documents of a collection purged after their retention
+func ObserveRetentionPurge(collection string, count int64) {
+	retentionPurged.WithLabelValues(collection).Add(float64(count))
+}
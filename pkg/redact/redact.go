@@ -0,0 +1,249 @@
+package redact
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maskPrefix marks the masked values
+const maskPrefix = "redacted:"
+
+var (
+	// paths of the redacted fields, split in their segments with the arrays marked by a [] suffix
+	paths [][]string
+	key   []byte
+)
+
+// allowedHeaders are the headers archived as is, the values of the others, for eg. the Authorization and the
+// beckn signature headers, are masked
+var allowedHeaders = []string{
+	"Accept",
+	"Accept-Encoding",
+	"Content-Encoding",
+	"Content-Length",
+	"Content-Type",
+	"Traceparent",
+	"Tracestate",
+	"User-Agent",
+	"X-Request-Id",
+}
+
+var (
+	// emailPattern matches the email addresses in a text
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// phonePattern matches the phone numbers in a text, ten digits with an optional country code
+	phonePattern = regexp.MustCompile(`(\+\d{1,3}[\s-]?)?\b\d{10}\b`)
+)
+
+// Init sets the paths of the redacted fields, for eg. customer.contact.phone or creds[].url, a path matches
+// any field it is the suffix of, and the key hashing the redacted values, a random key is used when no key
+// is given so the redacted values can't be guessed from their hash
+func Init(fieldPaths []string, hashKey string) error {
+	paths = nil
+	for _, path := range fieldPaths {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, strings.Split(path, "."))
+		}
+	}
+
+	key = []byte(hashKey)
+
+	if len(paths) > 0 && len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate redaction key, %v", err)
+		}
+
+		logrus.Warnf("[Redact]: No redaction key is set, the values are hashed with a random key and can't be correlated across restarts")
+	}
+
+	return nil
+}
+
+// Mask returns a keyed hash of a value in place of it, so the redacted values can still be correlated
+func Mask(value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return maskPrefix + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// JSON returns a json document with its redacted fields masked, the document is returned as is when it
+// isn't json or has no redacted fields
+func JSON(data []byte) []byte {
+	if len(paths) == 0 || len(data) == 0 {
+		return data
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return data
+	}
+
+	document, redacted := redact(nil, document)
+	if !redacted {
+		return data
+	}
+
+	redactedData, err := json.Marshal(document)
+	if err != nil {
+		return data
+	}
+
+	return redactedData
+}
+
+// Headers returns a copy of http headers with the values of the headers not allowed masked, the values
+// already masked are kept so the headers can be masked again, for eg. when they are read back
+func Headers(header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+
+	redacted := make(http.Header, len(header))
+
+	for name, values := range header {
+		if slices.Contains(allowedHeaders, http.CanonicalHeaderKey(name)) {
+			redacted[name] = slices.Clone(values)
+			continue
+		}
+
+		masked := make([]string, len(values))
+		for i, value := range values {
+			masked[i] = value
+			if !strings.HasPrefix(value, maskPrefix) {
+				masked[i] = Mask(value)
+			}
+		}
+		redacted[name] = masked
+	}
+
+	return redacted
+}
+
+// Text returns a text with the email addresses and the phone numbers in it masked, for eg. a log message
+// or an error quoting a document
+func Text(text string) string {
+	text = emailPattern.ReplaceAllStringFunc(text, Mask)
+	return phonePattern.ReplaceAllStringFunc(text, Mask)
+}
+
+// Hook redacts the fields of the log entries, the paths are matched from the field names, for eg. a
+// customer field is redacted by the customer.contact.phone path, the contact details are masked in the
+// messages and the errors as they are formatted as text
+type Hook struct{}
+
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	entry.Message = Text(entry.Message)
+
+	for field, value := range entry.Data {
+		if err, ok := value.(error); ok {
+			entry.Data[field] = Text(err.Error())
+			continue
+		}
+
+		if redactedValue, redacted := redact([]string{field}, toJSONValue(value)); redacted {
+			entry.Data[field] = redactedValue
+		}
+	}
+
+	return nil
+}
+
+// redact masks the redacted fields of a json value at the given path, and returns whether any was masked
+func redact(path []string, value interface{}) (interface{}, bool) {
+	if len(path) > 0 && isRedacted(path) {
+		if value == nil {
+			return nil, false
+		}
+
+		if s, ok := value.(string); ok {
+			return Mask(s), true
+		}
+
+		data, _ := json.Marshal(value)
+		return Mask(string(data)), true
+	}
+
+	var redacted bool
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			redactedValue, ok := redact(append(slices.Clip(path), k), v)
+			if ok {
+				value[k] = redactedValue
+				redacted = true
+			}
+		}
+	case []interface{}:
+		arrayPath := slices.Clone(path)
+		if len(arrayPath) > 0 {
+			arrayPath[len(arrayPath)-1] += "[]"
+		}
+
+		for i, v := range value {
+			redactedValue, ok := redact(arrayPath, v)
+			if ok {
+				value[i] = redactedValue
+				redacted = true
+			}
+		}
+	}
+
+	return value, redacted
+}
+
+// isRedacted returns whether a redacted path is the suffix of the given path
+func isRedacted(path []string) bool {
+	for _, redactedPath := range paths {
+		if len(redactedPath) <= len(path) && slices.Equal(redactedPath, path[len(path)-len(redactedPath):]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toJSONValue returns the json representation of a log field, for eg. the maps of the structs, so their
+// fields can be matched
+func toJSONValue(value interface{}) interface{} {
+	switch value.(type) {
+	case string, bool, int, int32, int64, float32, float64, nil:
+		return value
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	var jsonValue interface{}
+	if err := json.Unmarshal(data, &jsonValue); err != nil {
+		return value
+	}
+
+	return jsonValue
+}
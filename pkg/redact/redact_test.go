@@ -0,0 +1,256 @@
+package redact
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func initRedaction(t *testing.T, fieldPaths []string, hashKey string) {
+	t.Helper()
+
+	if err := Init(fieldPaths, hashKey); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { paths, key = nil, nil })
+}
+
+func TestJSON(t *testing.T) {
+	initRedaction(t, []string{"customer.contact.phone", "creds[].url", " ", "email"}, "secret")
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "nested field",
+			data: `{"customer":{"contact":{"phone":"9876543210"},"name":"A"}}`,
+			want: `{"customer":{"contact":{"phone":"` + Mask("9876543210") + `"},"name":"A"}}`,
+		},
+		{
+			name: "path matching the suffix of a field",
+			data: `{"order":{"fulfillments":[{"customer":{"contact":{"phone":"9876543210"}}}]}}`,
+			want: `{"order":{"fulfillments":[{"customer":{"contact":{"phone":"` + Mask("9876543210") + `"}}}]}}`,
+		},
+		{
+			name: "fields of an array",
+			data: `{"creds":[{"url":"https://a"},{"url":"https://b","type":"PAN"}]}`,
+			want: `{"creds":[{"url":"` + Mask("https://a") + `"},{"type":"PAN","url":"` + Mask("https://b") + `"}]}`,
+		},
+		{
+			name: "non string value",
+			data: `{"email":{"address":"a@b.c"}}`,
+			want: `{"email":"` + Mask(`{"address":"a@b.c"}`) + `"}`,
+		},
+		{
+			name: "null value",
+			data: `{"email":null}`,
+			want: `{"email":null}`,
+		},
+		{
+			name: "no redacted field",
+			data: `{"phone": "9876543210", "count": 1.50}`,
+			want: `{"phone": "9876543210", "count": 1.50}`,
+		},
+		{
+			name: "not json",
+			data: `phone=9876543210`,
+			want: `phone=9876543210`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(JSON([]byte(tt.data))); got != tt.want {
+				t.Fatalf("JSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONWithoutPaths(t *testing.T) {
+	initRedaction(t, nil, "secret")
+
+	data := `{"email":"a@b.c"}`
+	if got := string(JSON([]byte(data))); got != data {
+		t.Fatalf("JSON() = %s, want the document as is", got)
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name      string
+		paths     []string
+		keyA      string
+		keyB      string
+		a, b      string
+		wantEqual bool
+	}{
+		{name: "same value and key", keyA: "k", keyB: "k", a: "v", b: "v", wantEqual: true},
+		{name: "different values", keyA: "k", keyB: "k", a: "v", b: "w", wantEqual: false},
+		{name: "different keys", keyA: "k", keyB: "l", a: "v", b: "v", wantEqual: false},
+		{name: "random keys", paths: []string{"email"}, a: "v", b: "v", wantEqual: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initRedaction(t, tt.paths, tt.keyA)
+			a := Mask(tt.a)
+			initRedaction(t, tt.paths, tt.keyB)
+			b := Mask(tt.b)
+
+			if (a == b) != tt.wantEqual {
+				t.Fatalf("Mask(%q) = %s, Mask(%q) = %s, want equal %v", tt.a, a, tt.b, b, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	initRedaction(t, nil, "secret")
+
+	headers := http.Header{
+		"Authorization":  []string{`Signature keyId="bpp|key|ed25519"`},
+		"X-Custom-Token": []string{"a", "b"},
+		"Content-Type":   []string{"application/json"},
+		"x-request-id":   []string{"r1"},
+	}
+
+	want := http.Header{
+		"Authorization":  []string{Mask(`Signature keyId="bpp|key|ed25519"`)},
+		"X-Custom-Token": []string{Mask("a"), Mask("b")},
+		"Content-Type":   []string{"application/json"},
+		"x-request-id":   []string{"r1"},
+	}
+
+	got := Headers(headers)
+	for name, values := range want {
+		if !slices.Equal(got[name], values) {
+			t.Errorf("Headers()[%s] = %v, want %v", name, got[name], values)
+		}
+	}
+	if headers.Get("Authorization") != `Signature keyId="bpp|key|ed25519"` {
+		t.Fatalf("Headers() changed the headers given")
+	}
+	if again := Headers(got); !slices.Equal(again["Authorization"], want["Authorization"]) {
+		t.Errorf("Headers() masked again = %v, want %v", again["Authorization"], want["Authorization"])
+	}
+	if Headers(nil) != nil {
+		t.Fatalf("Headers(nil) != nil")
+	}
+}
+
+func TestText(t *testing.T) {
+	initRedaction(t, nil, "secret")
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "email",
+			text: "failed to notify a.b@c.com",
+			want: "failed to notify " + Mask("a.b@c.com"),
+		},
+		{
+			name: "phone with country code",
+			text: "duplicate key {phone: \"+91 9876543210\"}",
+			want: "duplicate key {phone: \"" + Mask("+91 9876543210") + "\"}",
+		},
+		{
+			name: "phone",
+			text: "applicant 9876543210 not found",
+			want: "applicant " + Mask("9876543210") + " not found",
+		},
+		{
+			name: "no contact details",
+			text: "job j1 closed at 2024-05-01 10:00, 12345 applications",
+			want: "job j1 closed at 2024-05-01 10:00, 12345 applications",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.text); got != tt.want {
+				t.Fatalf("Text() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookMessage(t *testing.T) {
+	initRedaction(t, []string{"email"}, "secret")
+
+	entry := &logrus.Entry{
+		Message: "Failed to update application of a@b.co",
+		Data:    logrus.Fields{logrus.ErrorKey: errors.New("duplicate phone 9876543210")},
+	}
+	if err := (&Hook{}).Fire(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Failed to update application of " + Mask("a@b.co"); entry.Message != want {
+		t.Errorf("Message = %s, want %s", entry.Message, want)
+	}
+	if want := "duplicate phone " + Mask("9876543210"); entry.Data[logrus.ErrorKey] != want {
+		t.Errorf("Data[%s] = %v, want %s", logrus.ErrorKey, entry.Data[logrus.ErrorKey], want)
+	}
+}
+
+func TestHook(t *testing.T) {
+	initRedaction(t, []string{"customer.contact.phone", "email"}, "secret")
+
+	type contact struct {
+		Phone string `json:"phone"`
+	}
+	type customer struct {
+		Contact contact `json:"contact"`
+	}
+
+	tests := []struct {
+		name  string
+		field string
+		value interface{}
+		want  interface{}
+	}{
+		{
+			name:  "string field",
+			field: "email",
+			value: "a@b.c",
+			want:  Mask("a@b.c"),
+		},
+		{
+			name:  "struct field",
+			field: "customer",
+			value: customer{Contact: contact{Phone: "9876543210"}},
+			want:  map[string]interface{}{"contact": map[string]interface{}{"phone": Mask("9876543210")}},
+		},
+		{
+			name:  "field not redacted",
+			field: "transaction_id",
+			value: "t1",
+			want:  "t1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &logrus.Entry{Data: logrus.Fields{tt.field: tt.value}}
+			if err := (&Hook{}).Fire(entry); err != nil {
+				t.Fatal(err)
+			}
+
+			got, _ := json.Marshal(entry.Data[tt.field])
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) {
+				t.Fatalf("Fire() = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/async"
+)
+
+var (
+	stop     = make(chan struct{})
+	stopOnce sync.Once
+)
+
+// Every runs the given task at the given interval in the background, the
+// task failures are logged and the task is retried at the next tick
+func Every(name string, interval time.Duration, task func() error) {
+	if interval <= 0 {
+		logrus.Warnf("[Scheduler]: Skipping %s, invalid interval %s", name, interval)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				async.Run(name, func() {
+					if err := task(); err != nil {
+						logrus.Errorf("[Scheduler]: Failed to run %s, %v", name, err)
+					}
+				})
+			}
+		}
+	}()
+
+	logrus.Infof("[Scheduler]: Scheduled %s every %s", name, interval)
+}
+
+// Stop stops running the scheduled tasks, the running tasks are waited for like the other background tasks
+func Stop() {
+	stopOnce.Do(func() { close(stop) })
+}
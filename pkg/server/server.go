@@ -1,23 +1,42 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ONEST-Network/Job-Manager-Adapter/api/middleware"
 	"github.com/ONEST-Network/Job-Manager-Adapter/api/routes"
 	"github.com/ONEST-Network/Job-Manager-Adapter/docs"
+	apiclient "github.com/ONEST-Network/Job-Manager-Adapter/pkg/api-client"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/async"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/clients"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb"
+	dbApplicantProfile "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/applicant-profile"
+	dbAudit "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
 	dbBusiness "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
 	dbInitJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/init-job-application"
 	dbJob "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
 	dbJobApplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	dbMessageArchive "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/message-archive"
+	dbRating "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/rating"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/scheduler"
 )
 
 func SetupServer(clients *clients.Clients) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	server := gin.New()
+	server.Use(middleware.RequestID())
+	server.Use(middleware.Trace())
 	server.Use(middleware.DefaultStructuredLogger())
 	server.Use(gin.Recovery())
 	server.Use(middleware.ValidateCors())
@@ -29,7 +48,9 @@ func SetupServer(clients *clients.Clients) *gin.Engine {
 
 	baseRouter := server.Group("/")
 	routes.BaseRouter(baseRouter, clients)
-	routes.BecknRouter(baseRouter, clients)
+
+	becknRouter := server.Group("/", middleware.BecknMetrics(), middleware.BecknContext(), middleware.ArchiveMessages(clients))
+	routes.BecknRouter(becknRouter, clients)
 
 	businessRouter := server.Group("/business")
 	routes.BusinessRouter(businessRouter, clients)
@@ -40,10 +61,13 @@ func SetupServer(clients *clients.Clients) *gin.Engine {
 	jobApplicationRouter := server.Group("/job-application")
 	routes.JobApplicationRouter(jobApplicationRouter, clients)
 
+	adminRouter := server.Group("/admin", middleware.ValidateAdminToken())
+	routes.AdminRouter(adminRouter, clients)
+
 	return server
 }
 
-func InitMongoDB() (*dbBusiness.Dao, *dbJob.Dao, *dbJobApplication.Dao, *dbInitJobApplication.Dao) {
+func InitMongoDB() (*dbBusiness.Dao, *dbJob.Dao, *dbJobApplication.Dao, *dbInitJobApplication.Dao, *dbRating.Dao, *dbApplicantProfile.Dao, *dbAudit.Dao, *dbMessageArchive.Dao) {
 	var err error
 
 	// Initialize mongodb clients
@@ -57,7 +81,63 @@ func InitMongoDB() (*dbBusiness.Dao, *dbJob.Dao, *dbJobApplication.Dao, *dbInitJ
 	business := dbBusiness.NewBusinessDao(mongodb.Client.BusinessCollection)
 	job := dbJob.NewJobDao(mongodb.Client.JobCollection)
 	jobApplication := dbJobApplication.NewJobApplicationDao(mongodb.Client.JobApplicationCollection)
-	initJobApplication := dbInitJobApplication.NewInitJobApplicationDao(mongodb.Client.InitJobApplicationCollection)
+	initJobApplication := dbInitJobApplication.NewInitJobApplicationDao(mongodb.Client.InitJobApplicationCollection, config.Config.InitJobApplicationRetention)
+	rating := dbRating.NewRatingDao(mongodb.Client.RatingCollection)
+	applicantProfile := dbApplicantProfile.NewApplicantProfileDao(mongodb.Client.ApplicantProfileCollection)
+	audit := dbAudit.NewAuditDao(mongodb.Client.AuditCollection)
+	messageArchive := dbMessageArchive.NewMessageArchiveDao(mongodb.Client.MessageArchiveCollection, config.Config.MessageArchiveRetention)
+
+	return business, job, jobApplication, initJobApplication, rating, applicantProfile, audit, messageArchive
+}
+
+// Serve serves the requests until the adapter is interrupted or terminated, and then shuts it down gracefully,
+// it stops accepting requests, completes the in-flight requests and background tasks within the drain
+// timeout and disconnects from the database
+func Serve(handler http.Handler) {
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%s", config.Config.HTTPPort),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-errs:
+		logrus.Fatalf("[Server]: Failed to serve, %v", err)
+	case <-signals.Done():
+	}
+
+	logrus.Infof("[Server]: Shutting down, draining for up to %s", config.Config.DrainTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Config.DrainTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logrus.Errorf("[Server]: Failed to complete the in-flight requests, %v", err)
+	}
+
+	scheduler.Stop()
+
+	// the abandoned beckn requests are in the message archive, to be replayed
+	for _, name := range async.Drain(ctx) {
+		logrus.Warnf("[Server]: Abandoned %s", name)
+	}
+
+	apiclient.CloseIdleConnections()
+
+	disconnectCtx, disconnectCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer disconnectCancel()
+
+	if err := mongodb.Disconnect(disconnectCtx); err != nil {
+		logrus.Errorf("[Server]: Failed to disconnect mongo client, %v", err)
+	}
 
-	return business, job, jobApplication, initJobApplication
+	logrus.Info("[Server]: Shut down")
 }
@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/config"
+)
+
+const tracerName = "github.com/ONEST-Network/Job-Manager-Adapter"
+
+// Message identifies the beckn message being processed, its attributes are added to all the spans
+// started while processing it so the spans of a transaction can be found together
+type Message struct {
+	Action        string
+	TransactionID string
+	MessageID     string
+	BapID         string
+}
+
+type messageKey struct{}
+
+// Init sets up the tracer provider for the configured exporter and returns its shutdown, which flushes
+// the pending spans, the spans are dropped when no exporter is configured
+func Init() (func(ctx context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch config.Config.TracingExporter {
+	case "":
+		return func(ctx context.Context) error { return nil }, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(context.Background())
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %s", config.Config.TracingExporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter, %v", config.Config.TracingExporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.Config.TracingSampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.Config.TracingServiceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span, with the attributes of the beckn message being processed, if any
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if message := GetMessage(ctx); message != nil {
+		opts = append(opts, trace.WithAttributes(message.attributes()...))
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End ends a span, marking it failed on error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// WithMessage returns a context processing the given beckn message, and adds its attributes to the
+// current span
+func WithMessage(ctx context.Context, message *Message) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(message.attributes()...)
+
+	return context.WithValue(ctx, messageKey{}, message)
+}
+
+// GetMessage returns the beckn message being processed in the context, if any
+func GetMessage(ctx context.Context) *Message {
+	message, _ := ctx.Value(messageKey{}).(*Message)
+	return message
+}
+
+// Inject adds the trace context to the headers of an outbound request
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns a context continuing the trace of an inbound request, if any
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+func (m *Message) attributes() []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+
+	for key, value := range map[string]string{
+		"beckn.action":         m.Action,
+		"beckn.transaction_id": m.TransactionID,
+		"beckn.message_id":     m.MessageID,
+		"beckn.bap_id":         m.BapID,
+	} {
+		if value != "" {
+			attributes = append(attributes, attribute.String(key, value))
+		}
+	}
+
+	return attributes
+}
@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"encoding/json"
+	"time"
+
+	applicantprofile "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/applicant-profile"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+	initjobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/init-job-application"
+	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	messagearchive "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/message-archive"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/rating"
+)
+
+// ApplicantDataRequest identifies an applicant by phone or email, or both
+type ApplicantDataRequest struct {
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+}
+
+type ExportApplicantDataResponse struct {
+	GeneratedAt         time.Time                               `json:"generatedAt"`
+	ApplicantProfiles   []applicantprofile.ApplicantProfile     `json:"applicantProfiles"`
+	InitJobApplications []initjobapplication.InitJobApplication `json:"initJobApplications"`
+	JobApplications     []jobapplication.JobApplication         `json:"jobApplications"`
+	Ratings             []rating.Rating                         `json:"ratings"`
+	Messages            []messagearchive.Message                `json:"messages"`
+	AuditEntries        []audit.AuditEntry                      `json:"auditEntries"`
+}
+
+type EraseApplicantDataResponse struct {
+	ErasedJobApplications      int `json:"erasedJobApplications"`
+	DeletedInitJobApplications int `json:"deletedInitJobApplications"`
+	DeletedApplicantProfiles   int `json:"deletedApplicantProfiles"`
+	DeletedMessages            int `json:"deletedMessages"`
+}
+
+type TransactionTimelineResponse struct {
+	TransactionID string               `json:"transactionId"`
+	Messages      []TransactionMessage `json:"messages"`
+}
+
+type TransactionMessage struct {
+	Direction     messagearchive.Direction `json:"direction"`
+	Action        string                   `json:"action"`
+	MessageID     string                   `json:"messageId"`
+	BapID         string                   `json:"bapId"`
+	Method        string                   `json:"method"`
+	URL           string                   `json:"url"`
+	Headers       map[string][]string      `json:"headers"`
+	Body          json.RawMessage          `json:"body" swaggertype:"object"`
+	StatusCode    int                      `json:"statusCode"`
+	ResponseBody  json.RawMessage          `json:"responseBody" swaggertype:"object"`
+	Ack           string                   `json:"ack"`
+	Error         string                   `json:"error,omitempty"`
+	LatencyMillis int64                    `json:"latencyMillis"`
+	CreatedAt     time.Time                `json:"createdAt"`
+}
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/audit"
+)
+
+type ListAuditEntriesRequest struct {
+	ActorType        audit.ActorType `form:"actorType"`
+	ActorID          string          `form:"actorId"`
+	Action           audit.Action    `form:"action"`
+	JobID            string          `form:"jobId"`
+	JobApplicationID string          `form:"jobApplicationId"`
+	CorrelationID    string          `form:"correlationId"`
+	From             *time.Time      `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To               *time.Time      `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit            int64           `form:"limit"`
+}
@@ -1,32 +1,71 @@
 package business
 
 import (
+	"time"
+
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/business"
 	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job"
+	jobapplication "github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/job-application"
+	"github.com/ONEST-Network/Job-Manager-Adapter/pkg/database/mongodb/rating"
 )
 
 type AddBusinessRequest struct {
-	ID             string            `json:"id"`
-	Name           string            `json:"name"`
-	Phone          string            `json:"phone"`
-	Email          string            `json:"email"`
-	PictureURLs    []string          `json:"pictureUrls"`
-	Description    string            `json:"description"`
-	GSTIndexNumber string            `json:"gstIndexNumber"`
-	Location       business.Location `json:"location"`
-	Industry       business.Industry `json:"industry"`
+	ID             string                `json:"id"`
+	Name           string                `json:"name"`
+	Phone          string                `json:"phone"`
+	Email          string                `json:"email"`
+	PictureURLs    []string              `json:"pictureUrls"`
+	Description    string                `json:"description"`
+	GSTIndexNumber string                `json:"gstIndexNumber"`
+	Location       business.Location     `json:"location"`
+	Industry       business.Industry     `json:"industry"`
+	Support        business.Support      `json:"support"`
+	ConsentTerms   business.ConsentTerms `json:"consentTerms"`
 }
 
 type ListJobsResponse struct {
-	ID             string          `json:"id"`
-	Name           string          `json:"name"`
-	Description    string          `json:"description"`
-	Type           job.JobType     `json:"type"`
-	Vacancies      int             `json:"vacancies"`
-	SalaryRange    job.SalaryRange `json:"salaryRange"`
-	ApplicationIDs []string        `json:"applicationIds"`
-	WorkHours      job.WorkHours   `json:"workHours"`
-	WorkDays       job.WorkDays    `json:"workDays"`
-	Eligibility    job.Eligibility `json:"eligibility"`
-	Location       job.Location    `json:"location"`
+	ID                 string          `json:"id"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	Type               job.JobType     `json:"type"`
+	Openings           int             `json:"openings"`
+	Hired              int             `json:"hired"`
+	RemainingVacancies int             `json:"remainingVacancies"`
+	ApplicationCap     int             `json:"applicationCap"`
+	Applications       int             `json:"applications"`
+	AvailableSlots     int             `json:"availableSlots"`
+	Waitlist           bool            `json:"waitlist"`
+	SalaryRange        job.SalaryRange `json:"salaryRange"`
+	ApplicationIDs     []string        `json:"applicationIds"`
+	WorkHours          job.WorkHours   `json:"workHours"`
+	WorkDays           job.WorkDays    `json:"workDays"`
+	Eligibility        job.Eligibility `json:"eligibility"`
+	Location           job.Location    `json:"location"`
+}
+
+type GetAnalyticsResponse struct {
+	Rating     RatingSummary `json:"rating"`
+	JobRatings []JobRating   `json:"jobRatings"`
+}
+
+type RatingSummary struct {
+	Average      float64               `json:"average"`
+	Count        int                   `json:"count"`
+	Distribution []rating.Distribution `json:"distribution"`
+}
+
+type JobRating struct {
+	JobID   string  `json:"jobId"`
+	Name    string  `json:"name"`
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+type CandidateApplication struct {
+	ID        string                              `json:"id"`
+	JobID     string                              `json:"jobId"`
+	JobName   string                              `json:"jobName"`
+	Status    jobapplication.JobApplicationStatus `json:"status"`
+	CreatedAt time.Time                           `json:"createdAt"`
+	UpdatedAt time.Time                           `json:"updatedAt"`
 }
@@ -0,0 +1,26 @@
+package health
+
+import "github.com/ONEST-Network/Job-Manager-Adapter/pkg/version"
+
+// Status represents whether the adapter, or one of its dependencies, is usable
+type Status string
+
+const (
+	StatusUp       Status = "UP"
+	StatusDown     Status = "DOWN"
+	StatusDisabled Status = "DISABLED"
+)
+
+type HealthResponse struct {
+	Status  Status           `json:"status"`
+	Version version.Info     `json:"version"`
+	Checks  map[string]Check `json:"checks,omitempty"`
+}
+
+// Check represents the status of a dependency of the adapter
+type Check struct {
+	Status        Status      `json:"status"`
+	LatencyMillis int64       `json:"latencyMillis"`
+	Details       interface{} `json:"details,omitempty"`
+	Error         string      `json:"error,omitempty"`
+}
@@ -1,6 +1,16 @@
 package jobapplication
 
+import "time"
+
 type UpdateJobApplicationStatusRequest struct {
 	// @Enum(APPLICATION_ACCEPTED, APPLICATION_REJECTED, ASSESSMENT_IN_PROGRESS, OFFER_REJECTED, OFFER_ACCEPTED, OFFER_EXTENDED, CANCELLED)
 	Status string `json:"status"`
 }
+
+type ExtendOfferRequest struct {
+	Salary      int       `json:"salary"`
+	JoiningDate time.Time `json:"joiningDate"`
+	Location    string    `json:"location"`
+	// ExpiresAt is optional, the offer validity from the config is used when it is not provided
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
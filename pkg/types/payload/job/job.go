@@ -6,20 +6,38 @@ import (
 )
 
 type CreateJobRequest struct {
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	Type        job.JobType     `json:"type"`
-	Vacancies   int             `json:"vacancies"`
-	SalaryRange job.SalaryRange `json:"salaryRange"`
-	WorkHours   job.WorkHours   `json:"workHours"`
-	WorkDays    job.WorkDays    `json:"workDays"`
-	Eligibility job.Eligibility `json:"eligibility"`
-	Location    job.Location    `json:"location"`
-	BusinessID  string          `json:"businessId"`
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	Type           job.JobType     `json:"type"`
+	Openings       int             `json:"openings"`
+	Vacancies      int             `json:"vacancies,omitempty"` // Deprecated: the former name of openings, used when openings isn't set
+	ApplicationCap int             `json:"applicationCap"`      // maximum in-progress applications, 0 for as many as the openings
+	Waitlist       bool            `json:"waitlist"`
+	SalaryRange    job.SalaryRange `json:"salaryRange"`
+	WorkHours      job.WorkHours   `json:"workHours"`
+	WorkDays       job.WorkDays    `json:"workDays"`
+	Eligibility    job.Eligibility `json:"eligibility"`
+	Location       job.Location    `json:"location"`
+	BusinessID     string          `json:"businessId"`
 }
 
 type GetJobApplicationsResponse struct {
 	ID               string                              `json:"id"`
 	ApplicantDetails jobapplication.ApplicantDetails     `json:"applicantDetails"`
 	Status           jobapplication.JobApplicationStatus `json:"status"`
+	Consent          *jobapplication.Consent             `json:"consent,omitempty"`
+	ProfileID        string                              `json:"profileId,omitempty"`
+	Duplicate        bool                                `json:"duplicate"`
+	DuplicateOf      string                              `json:"duplicateOf,omitempty"`
+}
+
+type CloseJobRequest struct {
+	// @Enum(FILLED, WITHDRAWN)
+	Reason      job.ClosureReason `json:"reason"`
+	Description string            `json:"description"`
+}
+
+type CloseJobResponse struct {
+	ID                 string `json:"id"`
+	ClosedApplications int    `json:"closedApplications"`
 }
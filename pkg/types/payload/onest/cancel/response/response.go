@@ -93,6 +93,18 @@ type Payments struct {
 	CollectedBy string `json:"collected_by"`
 	Tags        Tags   `json:"tags"`
 }
+type ReasonDescriptor struct {
+	Code      string `json:"code"`
+	ShortDesc string `json:"short_desc"`
+}
+type Reason struct {
+	ID         string           `json:"id"`
+	Descriptor ReasonDescriptor `json:"descriptor"`
+}
+type Cancellation struct {
+	CancelledBy string `json:"cancelled_by"`
+	Reason      Reason `json:"reason"`
+}
 type Order struct {
 	ID           string         `json:"id"`
 	Status       string         `json:"status"`
@@ -101,6 +113,7 @@ type Order struct {
 	Quote        Quote          `json:"quote"`
 	Fulfillments []Fulfillments `json:"fulfillments"`
 	Payments     []Payments     `json:"payments"`
+	Cancellation *Cancellation  `json:"cancellation,omitempty"`
 }
 type Message struct {
 	Order Order `json:"order"`
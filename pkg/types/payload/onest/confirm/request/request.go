@@ -130,6 +130,7 @@ type Order struct {
 	Fulfillments []Fulfillments `json:"fulfillments"`
 	Quote        Quote          `json:"quote"`
 	Payments     []Payments     `json:"payments"`
+	Tags         []Tags         `json:"tags"`
 }
 type Message struct {
 	Order Order `json:"order"`
@@ -3,6 +3,7 @@ package response
 type ConfirmResponse struct {
 	Context Context `json:"context"`
 	Message Message `json:"message"`
+	Error   *Error  `json:"error,omitempty"`
 }
 type City struct {
 	Code string `json:"code"`
@@ -122,15 +123,29 @@ type Payments struct {
 	CollectedBy string `json:"collected_by"`
 	Tags        Tags   `json:"tags"`
 }
+type FulfillmentState struct {
+	Descriptor StateDescriptor `json:"descriptor"`
+}
+type CancellationTerms struct {
+	FulfillmentState FulfillmentState `json:"fulfillment_state"`
+	ReasonRequired   bool             `json:"reason_required"`
+	Tags             []Tags           `json:"tags,omitempty"`
+}
 type Order struct {
-	ID           string         `json:"id"`
-	Status       string         `json:"status"`
-	Provider     Provider       `json:"provider"`
-	Items        []Items        `json:"items"`
-	Quote        Quote          `json:"quote"`
-	Fulfillments []Fulfillments `json:"fulfillments"`
-	Payments     []Payments     `json:"payments"`
+	ID                string              `json:"id"`
+	Status            string              `json:"status"`
+	Provider          Provider            `json:"provider"`
+	Items             []Items             `json:"items"`
+	Quote             Quote               `json:"quote"`
+	Fulfillments      []Fulfillments      `json:"fulfillments"`
+	Payments          []Payments          `json:"payments"`
+	CancellationTerms []CancellationTerms `json:"cancellation_terms,omitempty"`
 }
 type Message struct {
 	Order Order `json:"order"`
 }
+type Error struct {
+	Code    string `json:"code"`
+	Paths   string `json:"paths"`
+	Message string `json:"message"`
+}
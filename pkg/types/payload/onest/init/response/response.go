@@ -140,12 +140,22 @@ type Payments struct {
 	CollectedBy string `json:"collected_by"`
 	Tags        Tags   `json:"tags"`
 }
+type FulfillmentState struct {
+	Descriptor StateDescriptor `json:"descriptor"`
+}
+type CancellationTerms struct {
+	FulfillmentState FulfillmentState `json:"fulfillment_state"`
+	ReasonRequired   bool             `json:"reason_required"`
+	Tags             []Tags           `json:"tags,omitempty"`
+}
 type Order struct {
-	Provider     Provider       `json:"provider"`
-	Items        []Items        `json:"items"`
-	Quote        Quote          `json:"quote"`
-	Fulfillments []Fulfillments `json:"fulfillments"`
-	Payments     []Payments     `json:"payments"`
+	Provider          Provider            `json:"provider"`
+	Items             []Items             `json:"items"`
+	Quote             Quote               `json:"quote"`
+	Fulfillments      []Fulfillments      `json:"fulfillments"`
+	Payments          []Payments          `json:"payments"`
+	CancellationTerms []CancellationTerms `json:"cancellation_terms,omitempty"`
+	Tags              []Tags              `json:"tags,omitempty"`
 }
 type Message struct {
 	Order Order `json:"order"`
@@ -0,0 +1,55 @@
+package response
+
+type RatingResponse struct {
+	Context Context `json:"context"`
+	Message Message `json:"message"`
+	Error   *Error  `json:"error,omitempty"`
+}
+type City struct {
+	Code string `json:"code"`
+}
+type Country struct {
+	Code string `json:"code"`
+}
+type Location struct {
+	City    City    `json:"city"`
+	Country Country `json:"country"`
+}
+type Context struct {
+	Domain        string   `json:"domain"`
+	Action        string   `json:"action"`
+	Version       string   `json:"version"`
+	BapID         string   `json:"bap_id"`
+	BapURI        string   `json:"bap_uri"`
+	BppID         string   `json:"bpp_id"`
+	BppURI        string   `json:"bpp_uri"`
+	TransactionID string   `json:"transaction_id"`
+	MessageID     string   `json:"message_id"`
+	Location      Location `json:"location"`
+	Timestamp     string   `json:"timestamp"`
+	TTL           string   `json:"ttl"`
+}
+type Form struct {
+	MimeType string `json:"mime_type"`
+	URL      string `json:"url"`
+}
+type FeedbackForm struct {
+	Form     Form `json:"form"`
+	Required bool `json:"required"`
+}
+type Ratings struct {
+	ID             string `json:"id"`
+	RatingCategory string `json:"rating_category"`
+	Value          string `json:"value"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason,omitempty"`
+}
+type Message struct {
+	FeedbackForm *FeedbackForm `json:"feedback_form,omitempty"`
+	Ratings      []Ratings     `json:"ratings,omitempty"`
+}
+type Error struct {
+	Code    string `json:"code"`
+	Paths   string `json:"paths"`
+	Message string `json:"message"`
+}
@@ -101,6 +101,7 @@ type Contact struct {
 }
 type Creator struct {
 	Descriptor CreatorDescriptor `json:"descriptor"`
+	Rating     string            `json:"rating,omitempty"`
 	Address    string            `json:"address"`
 	State      State             `json:"state"`
 	City       City              `json:"city"`
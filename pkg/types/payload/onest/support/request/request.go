@@ -0,0 +1,38 @@
+package request
+
+type SupportRequest struct {
+	Context Context `json:"context"`
+	Message Message `json:"message"`
+}
+type City struct {
+	Code string `json:"code"`
+}
+type Country struct {
+	Code string `json:"code"`
+}
+type Location struct {
+	City    City    `json:"city"`
+	Country Country `json:"country"`
+}
+type Context struct {
+	Domain        string   `json:"domain"`
+	Action        string   `json:"action"`
+	Version       string   `json:"version"`
+	BapID         string   `json:"bap_id"`
+	BapURI        string   `json:"bap_uri"`
+	BppID         string   `json:"bpp_id"`
+	BppURI        string   `json:"bpp_uri"`
+	TransactionID string   `json:"transaction_id"`
+	MessageID     string   `json:"message_id"`
+	Location      Location `json:"location"`
+	Timestamp     string   `json:"timestamp"`
+	TTL           string   `json:"ttl"`
+}
+type Support struct {
+	OrderID       string `json:"order_id"`
+	RefID         string `json:"ref_id"`
+	CallbackPhone string `json:"callback_phone"`
+}
+type Message struct {
+	Support Support `json:"support"`
+}
@@ -0,0 +1,31 @@
+package contact
+
+import (
+	"strings"
+	"unicode"
+)
+
+// phoneDigits is the number of digits of a phone number without the country code
+const phoneDigits = 10
+
+// NormalizePhone returns the phone number with only its last 10 digits, so the same number written
+// with or without the country code, spaces or dashes is matched, for eg. '+91 98450-12345'
+func NormalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+
+	if len(digits) > phoneDigits {
+		digits = digits[len(digits)-phoneDigits:]
+	}
+
+	return digits
+}
+
+// NormalizeEmail returns the email address trimmed and in lower case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
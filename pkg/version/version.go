@@ -0,0 +1,44 @@
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version of the adapter, set at build time with -ldflags "-X github.com/ONEST-Network/Job-Manager-Adapter/pkg/version.Version=<version>"
+var Version = "dev"
+
+// Info represents the version and the build of the adapter
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the version of the adapter along with the commit it was built from, when built in the repository
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GoVersion: runtime.Version(),
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.BuildTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}